@@ -4,17 +4,23 @@ package binarytree
 
 import (
 	"errors"
-	"fmt"
 	"github.com/emnl/goods/queue"
 	"github.com/emnl/goods/stack"
+	"reflect"
 )
 
-// A binarytree has a size, a pointer to the root node, and
-// a user defined function which is used to compare the node's element.
+// A binarytree has a size, a pointer to the root node,
+// a user defined function which is used to compare the node's element,
+// and optionally the type of the elements, used when decoding JSON.
+//
+// After a Split the size of the new trees is not known yet; it is
+// counted the first time Size is called.
 type BinaryTree struct {
-	less LessFunc
-	size int
-	root *node
+	less      LessFunc
+	size      int
+	root      *node
+	elemType  reflect.Type
+	uncounted bool
 }
 
 // The binarytree is made up of nodes with an element,
@@ -42,7 +48,7 @@ type LessFunc func(a, b interface{}) bool
 // e.g. mytree := binarytree.New(intLess)
 //
 func New(lf LessFunc) *BinaryTree {
-	bt := BinaryTree{lf, 0, nil}
+	bt := BinaryTree{lf, 0, nil, nil, false}
 	return &bt
 }
 
@@ -51,6 +57,10 @@ func New(lf LessFunc) *BinaryTree {
 // e.g. (2 (1) (3)).Size() => 3
 //
 func (T *BinaryTree) Size() int {
+	if T.uncounted {
+		T.size = count(T.root)
+		T.uncounted = false
+	}
 	return T.size
 }
 
@@ -60,7 +70,7 @@ func (T *BinaryTree) Size() int {
 //      ().Empty() => true
 //
 func (T *BinaryTree) Empty() bool {
-	return T.size == 0
+	return T.root == nil
 }
 
 // Add adds the given element to the tree.
@@ -122,18 +132,6 @@ func (T *BinaryTree) Last() Elem {
 	return T.root.findMax().elem
 }
 
-// PrintTree prints the tree in the console. It is used as a
-// debugging tool.
-func (T *BinaryTree) PrintTree() {
-	if T.Empty() {
-		fmt.Println("Empty tree")
-		return
-	}
-	fmt.Print("\n")
-	print(T.root, 0)
-	fmt.Print("\n")
-}
-
 // InOrder returns an iterator over the tree depth-first inorder:
 // Visit the root.
 // Traverse the left subtree.
@@ -142,7 +140,7 @@ func (T *BinaryTree) PrintTree() {
 // e.g. for x := range (2 (1) (3)).InOrder() { x } => 1, 2, 3
 //
 func (T *BinaryTree) InOrder() chan Elem {
-	ch := make(chan Elem, T.size)
+	ch := make(chan Elem, T.Size())
 	go func() {
 
 		nodes := stack.New()
@@ -177,7 +175,7 @@ func (T *BinaryTree) InOrder() chan Elem {
 // e.g. for x := range (2 (1) (3)).PreOrder() { x } => 2, 1, 3
 //
 func (T *BinaryTree) PreOrder() chan Elem {
-	ch := make(chan Elem, T.size)
+	ch := make(chan Elem, T.Size())
 	go func() {
 
 		if T.Empty() {
@@ -214,7 +212,7 @@ func (T *BinaryTree) PreOrder() chan Elem {
 // e.g. for x := range (2 (1) (3)).PostOrder() { x } => 1, 3, 2
 //
 func (T *BinaryTree) PostOrder() chan Elem {
-	ch := make(chan Elem, T.size)
+	ch := make(chan Elem, T.Size())
 	go func() {
 
 		if T.Empty() {
@@ -257,7 +255,7 @@ func (T *BinaryTree) PostOrder() chan Elem {
 // e.g. for x := range (2 (1) (3)).LevelOrder() { x } => 2, 1, 3
 //
 func (T *BinaryTree) LevelOrder() chan Elem {
-	ch := make(chan Elem, T.size)
+	ch := make(chan Elem, T.Size())
 	go func() {
 
 		if T.Empty() {
@@ -410,17 +408,3 @@ func (N *node) findMax() *node {
 	}
 	return found
 }
-
-// print is used with debugging. It prints a simple tree
-// representation.
-func print(N *node, padding int) {
-	if N != nil {
-		newp := padding + 3
-		print(N.left, newp)
-		for i := 0; i < padding; i++ {
-			fmt.Print("-")
-		}
-		fmt.Printf("%d \n", N.elem)
-		print(N.right, newp)
-	}
-}
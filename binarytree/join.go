@@ -0,0 +1,88 @@
+package binarytree
+
+import "errors"
+
+// Split divides the tree in two: one tree with the elements less than
+// the given element, and one with the elements equal or bigger. The
+// nodes are moved to the new trees, leaving the tree empty.
+//
+// The tree isn't balanced, so the split follows a single path from
+// the root, and runs in O(h) for a tree of height h. The new trees
+// count their elements the first time Size is called.
+//
+// e.g. (2 (1) (3)).Split(2) => (1 () ()), (2 () (3))
+//
+func (T *BinaryTree) Split(E Elem) (*BinaryTree, *BinaryTree) {
+	l, r := split(T.root, E, T.less)
+
+	left, right := New(T.less), New(T.less)
+	left.root, right.root = l, r
+	left.uncounted, right.uncounted = l != nil, r != nil
+
+	T.root = nil
+	T.size = 0
+	T.uncounted = false
+
+	return left, right
+}
+
+// Join concatenates two trees into one. Every element in left must
+// be less than every element in right, and both trees must use the
+// same LessFunc; the new tree uses the one of left. The nodes are
+// moved to the new tree, leaving both trees empty. It runs in O(h)
+// for a left tree of height h.
+//
+// e.g. Join((1 () ()), (3 (2) ())) => (1 () (3 (2) ()))
+//
+func Join(left, right *BinaryTree) (*BinaryTree, error) {
+	if left == right {
+		return nil, errors.New("Can't join a tree with itself.")
+	}
+	if !left.Empty() && !right.Empty() && !left.less(left.Last(), right.First()) {
+		return nil, errors.New("Trees overlap.")
+	}
+
+	T := New(left.less)
+	T.size = left.size + right.size
+	T.uncounted = left.uncounted || right.uncounted
+
+	if left.root == nil {
+		T.root = right.root
+	} else {
+		T.root = left.root
+		left.root.findMax().right = right.root
+	}
+
+	left.root, right.root = nil, nil
+	left.size, right.size = 0, 0
+	left.uncounted, right.uncounted = false, false
+
+	return T, nil
+}
+
+// split takes the subtree apart around the given element. It returns
+// the subtree with the smaller elements and the subtree with the
+// equal or bigger elements.
+func split(N *node, E Elem, less LessFunc) (*node, *node) {
+	if N == nil {
+		return nil, nil
+	}
+
+	if less(N.elem, E) {
+		l, r := split(N.right, E, less)
+		N.right = l
+		return N, r
+	}
+
+	l, r := split(N.left, E, less)
+	N.left = r
+	return l, N
+}
+
+// count returns the number of nodes in the subtree.
+func count(N *node) int {
+	if N == nil {
+		return 0
+	}
+	return count(N.left) + count(N.right) + 1
+}
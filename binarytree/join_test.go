@@ -0,0 +1,86 @@
+package binarytree
+
+import "testing"
+
+// toSlice returns the elements of the tree in order.
+func toSlice(tree *BinaryTree) []int {
+	res := []int{}
+	for x := range tree.InOrder() {
+		res = append(res, x.(int))
+	}
+	return res
+}
+
+func TestSplit(t *testing.T) {
+	for key := 0; key <= 11; key++ {
+		tree := New(intLess)
+		for _, x := range []int{5, 2, 8, 1, 3, 7, 9, 4, 6} {
+			tree.Add(x)
+		}
+
+		left, right := tree.Split(key)
+
+		if !tree.Empty() {
+			t.Errorf("Split should leave the tree empty.")
+		}
+		if left.Size() != len(toSlice(left)) || right.Size() != len(toSlice(right)) {
+			t.Errorf("Split should keep the sizes right.")
+		}
+		if left.Size()+right.Size() != 9 {
+			t.Errorf("Split should keep every element.")
+		}
+		for _, x := range toSlice(left) {
+			if x >= key {
+				t.Errorf("The left tree should only hold smaller elements.")
+			}
+		}
+		for _, x := range toSlice(right) {
+			if x < key {
+				t.Errorf("The right tree should only hold equal or bigger elements.")
+			}
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	left, right := New(intLess), New(intLess)
+	left.Add(2)
+	left.Add(1)
+	right.Add(4)
+	right.Add(3)
+
+	tree, err := Join(left, right)
+	if err != nil {
+		t.Fatalf("Join should accept trees that don't overlap.")
+	}
+
+	res := toSlice(tree)
+	if tree.Size() != 4 || len(res) != 4 {
+		t.Errorf("Join should keep every element.")
+	}
+	for i, x := range res {
+		if x != i+1 {
+			t.Errorf("Join should keep the elements in order.")
+		}
+	}
+	if !left.Empty() || !right.Empty() {
+		t.Errorf("Join should leave both trees empty.")
+	}
+
+	left.Add(5)
+	if _, err := Join(left, tree); err == nil {
+		t.Errorf("Join should refuse overlapping trees.")
+	}
+	if _, err := Join(left, left); err == nil || left.Size() != 1 {
+		t.Errorf("Join should refuse to join a tree with itself.")
+	}
+
+	/* A split tree is counted lazily, and a join keeps it so */
+	l, r := tree.Split(3)
+	l.Add(0)
+	r.Remove(4)
+	joined, _ := Join(l, r)
+	if joined.Size() != 4 || l.Size() != 0 || r.Size() != 0 {
+		t.Errorf("Join should keep the size of split trees, got %d.", joined.Size())
+	}
+}
@@ -0,0 +1,159 @@
+package binarytree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The binary format starts with a magic string and a version byte,
+// followed by the elements in order, encoded with encoding/gob.
+// Elements of user defined types must be registered with gob.Register.
+const (
+	magic   = "GBST"
+	version = 1
+)
+
+// payload is the gob encoded part of the binary format.
+type payload struct {
+	Size  int
+	Elems []Elem
+}
+
+// SetElemType tells UnmarshalJSON which type the elements have.
+// Without it, JSON numbers are decoded as float64, objects as
+// map[string]interface{} and so on.
+//
+// e.g. mytree.SetElemType(0) // the elements are ints
+//
+func (T *BinaryTree) SetElemType(example Elem) {
+	T.elemType = reflect.TypeOf(example)
+}
+
+// MarshalBinary encodes the elements of the tree in order.
+// It implements encoding.BinaryMarshaler.
+func (T *BinaryTree) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString(magic)
+	buf.WriteByte(version)
+
+	if err := gob.NewEncoder(buf).Encode(payload{T.Size(), T.elems()}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the elements of the tree with the ones
+// encoded by MarshalBinary. The tree must have a LessFunc, so it should
+// be created with New first. It implements encoding.BinaryUnmarshaler.
+//
+// e.g. mytree := binarytree.New(intLess)
+//      err := mytree.UnmarshalBinary(data)
+//
+func (T *BinaryTree) UnmarshalBinary(data []byte) error {
+	if len(data) < len(magic)+1 || string(data[:len(magic)]) != magic {
+		return errors.New("Data is not an encoded Tree.")
+	}
+	if data[len(magic)] != version {
+		return fmt.Errorf("Unsupported Tree encoding version %d.", data[len(magic)])
+	}
+
+	var p payload
+	if err := gob.NewDecoder(bytes.NewReader(data[len(magic)+1:])).Decode(&p); err != nil {
+		return fmt.Errorf("Corrupt Tree data: %v", err)
+	}
+	if p.Size != len(p.Elems) {
+		return errors.New("Corrupt Tree data: size mismatch.")
+	}
+
+	return T.restore(p.Elems)
+}
+
+// MarshalJSON encodes the tree as a JSON array of its elements,
+// in order. It implements json.Marshaler.
+//
+// e.g. (2 (1) (3)) => [1,2,3]
+//
+func (T *BinaryTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(T.elems())
+}
+
+// UnmarshalJSON replaces the elements of the tree with the ones in a
+// JSON array. The tree must have a LessFunc, and SetElemType decides
+// the type of the elements. It implements json.Unmarshaler.
+//
+// e.g. mytree := binarytree.New(intLess)
+//      mytree.SetElemType(0)
+//      err := json.Unmarshal([]byte("[1,2,3]"), mytree)
+//
+func (T *BinaryTree) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("Corrupt Tree data: %v", err)
+	}
+
+	elems := make([]Elem, len(raw))
+	for i, r := range raw {
+		if T.elemType == nil {
+			if err := json.Unmarshal(r, &elems[i]); err != nil {
+				return fmt.Errorf("Corrupt Tree data: %v", err)
+			}
+			continue
+		}
+
+		v := reflect.New(T.elemType)
+		if err := json.Unmarshal(r, v.Interface()); err != nil {
+			return fmt.Errorf("Corrupt Tree data: %v", err)
+		}
+		elems[i] = v.Elem().Interface()
+	}
+
+	return T.restore(elems)
+}
+
+// build creates a balanced subtree from sorted elements.
+func build(elems []Elem) *node {
+	if len(elems) == 0 {
+		return nil
+	}
+	mid := len(elems) / 2
+	return &node{elems[mid], build(elems[:mid]), build(elems[mid+1:])}
+}
+
+// elems returns the elements of the tree in order.
+func (T *BinaryTree) elems() []Elem {
+	res := make([]Elem, 0, T.Size())
+	inOrder(T.root, func(n *node) {
+		res = append(res, n.elem)
+	})
+	return res
+}
+
+// restore replaces the elements of the tree with the given ones,
+// which must be sorted. The tree is left untouched on failure.
+func (T *BinaryTree) restore(elems []Elem) (err error) {
+	if T.less == nil {
+		return errors.New("The Tree has no LessFunc.")
+	}
+
+	/* The LessFunc may panic on elements of an unexpected type */
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Corrupt Tree data: %v", r)
+		}
+	}()
+
+	for i := 1; i < len(elems); i++ {
+		if !T.less(elems[i-1], elems[i]) {
+			return errors.New("Corrupt Tree data: elements are not sorted.")
+		}
+	}
+
+	T.root = build(elems)
+	T.size = len(elems)
+	T.uncounted = false
+	return nil
+}
@@ -0,0 +1,116 @@
+package binarytree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// fromSlice returns a tree with the given elements.
+func fromSlice(slc []int) *BinaryTree {
+	tree := New(intLess)
+	for _, x := range slc {
+		tree.Add(x)
+	}
+	return tree
+}
+
+// sameElements returns true if both trees hold the same elements.
+func sameElements(a, b *BinaryTree) bool {
+	x, y := toSlice(a), toSlice(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMarshalBinary(t *testing.T) {
+	tree := New(intLess)
+	for x := 0; x < 50; x++ {
+		tree.Add((x * 7) % 50)
+	}
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	res := New(intLess)
+	if err := res.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if !sameElements(res, tree) {
+		t.Errorf("UnmarshalBinary should restore every element.")
+	}
+
+	if res.root.left == nil || res.root.right == nil {
+		t.Errorf("UnmarshalBinary should build a balanced tree.")
+	}
+}
+
+func TestUnmarshalBinaryCorrupt(t *testing.T) {
+	tree := fromSlice([]int{1, 2, 3})
+	data, _ := tree.MarshalBinary()
+
+	res := fromSlice([]int{9})
+
+	if res.UnmarshalBinary([]byte("nonsense")) == nil {
+		t.Errorf("UnmarshalBinary should refuse foreign data.")
+	}
+	if res.UnmarshalBinary(data[:len(data)-3]) == nil {
+		t.Errorf("UnmarshalBinary should refuse truncated data.")
+	}
+
+	bad := append([]byte{}, data...)
+	bad[len(magic)] = 99
+	if res.UnmarshalBinary(bad) == nil {
+		t.Errorf("UnmarshalBinary should refuse unknown versions.")
+	}
+
+	if res.Size() != 1 || !res.Contains(9) {
+		t.Errorf("A failed UnmarshalBinary should leave the tree untouched.")
+	}
+
+	if new(BinaryTree).UnmarshalBinary(data) == nil {
+		t.Errorf("UnmarshalBinary should need a LessFunc.")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tree := fromSlice([]int{3, 1, 2})
+
+	data, err := json.Marshal(tree)
+	if err != nil || string(data) != "[1,2,3]" {
+		t.Errorf("MarshalJSON should write the elements in order, got %s.", data)
+	}
+
+	res := New(intLess)
+	res.SetElemType(0)
+	if err := json.Unmarshal(data, res); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if !sameElements(res, tree) {
+		t.Errorf("UnmarshalJSON should restore every element.")
+	}
+
+	if json.Unmarshal([]byte("[3,2,1]"), res) == nil {
+		t.Errorf("UnmarshalJSON should refuse unsorted elements.")
+	}
+	if json.Unmarshal([]byte(`["a","b"]`), res) == nil {
+		t.Errorf("UnmarshalJSON should refuse elements of the wrong type.")
+	}
+	if !sameElements(res, tree) {
+		t.Errorf("A failed UnmarshalJSON should leave the tree untouched.")
+	}
+
+	generic := New(func(a, b interface{}) bool { return a.(float64) < b.(float64) })
+	if err := json.Unmarshal(data, generic); err != nil || generic.First() != 1.0 {
+		t.Errorf("UnmarshalJSON should decode numbers as float64 by default.")
+	}
+}
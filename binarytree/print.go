@@ -0,0 +1,233 @@
+package binarytree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Layout decides how Fprint draws the tree.
+type Layout int
+
+const (
+	// Sideways draws the tree rotated to the right, one node per line,
+	// with the smallest element on top.
+	Sideways Layout = iota
+
+	// TopDown draws the tree with the root on top and the
+	// children below.
+	TopDown
+)
+
+// PrintOptions configures the output of Fprint, WriteDOT and WriteJSON.
+// A nil *PrintOptions gives the defaults.
+type PrintOptions struct {
+	// Format turns an element into text. It defaults to fmt.Sprint.
+	Format func(Elem) string
+
+	// Layout is used by Fprint, it defaults to Sideways.
+	Layout Layout
+}
+
+// PrintTree prints the tree in the console. It is used as a
+// debugging tool.
+func (T *BinaryTree) PrintTree() {
+	if T.Empty() {
+		fmt.Println("Empty tree")
+		return
+	}
+	fmt.Print("\n")
+	T.Fprint(os.Stdout, nil)
+	fmt.Print("\n")
+}
+
+// Fprint writes a drawing of the tree to w.
+//
+// e.g. (2 (1) (3)).Fprint(w, &PrintOptions{Layout: TopDown})
+//
+//	 _2_
+//	/   \
+//	1   3
+//
+func (T *BinaryTree) Fprint(w io.Writer, opts *PrintOptions) error {
+	o := options(opts)
+	buf := new(bytes.Buffer)
+
+	switch {
+	case T.Empty():
+		buf.WriteString("Empty tree\n")
+	case o.Layout == TopDown:
+		topDown(buf, T.root, o)
+	default:
+		sideways(buf, T.root, 0, o)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteDOT writes the tree to w in the Graphviz DOT language.
+//
+// e.g. tree.WriteDOT(w, nil) | dot -Tpng > tree.png
+//
+func (T *BinaryTree) WriteDOT(w io.Writer, opts *PrintOptions) error {
+	o := options(opts)
+	buf := new(bytes.Buffer)
+	ids := map[*node]int{}
+
+	buf.WriteString("digraph tree {\n")
+	buf.WriteString("\tnode [shape=circle];\n")
+
+	inOrder(T.root, func(n *node) {
+		ids[n] = len(ids)
+		fmt.Fprintf(buf, "\tn%d [label=%q];\n", ids[n], o.Format(n.elem))
+	})
+
+	inOrder(T.root, func(n *node) {
+		if n.left != nil {
+			fmt.Fprintf(buf, "\tn%d -> n%d;\n", ids[n], ids[n.left])
+		}
+		if n.right != nil {
+			fmt.Fprintf(buf, "\tn%d -> n%d;\n", ids[n], ids[n.right])
+		}
+	})
+
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteJSON writes the structure of the tree to w as nested JSON
+// objects. The elements are written as they are, or as strings if
+// a Format function is given. An empty tree is written as null.
+//
+// e.g. (2 (1) ()) => {"elem":2,"left":{"elem":1}}
+//
+func (T *BinaryTree) WriteJSON(w io.Writer, opts *PrintOptions) error {
+	var format func(Elem) string
+	if opts != nil {
+		format = opts.Format
+	}
+	return json.NewEncoder(w).Encode(structure(T.root, format))
+}
+
+// jsonNode is the JSON representation of a node.
+type jsonNode struct {
+	Elem  interface{} `json:"elem"`
+	Left  *jsonNode   `json:"left,omitempty"`
+	Right *jsonNode   `json:"right,omitempty"`
+}
+
+// structure converts the subtree into jsonNodes.
+func structure(N *node, format func(Elem) string) *jsonNode {
+	if N == nil {
+		return nil
+	}
+
+	j := &jsonNode{N.elem, structure(N.left, format), structure(N.right, format)}
+	if format != nil {
+		j.Elem = format(N.elem)
+	}
+	return j
+}
+
+// options fills in the defaults.
+func options(opts *PrintOptions) PrintOptions {
+	o := PrintOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Format == nil {
+		o.Format = func(E Elem) string { return fmt.Sprint(E) }
+	}
+	return o
+}
+
+// label returns the text of a node.
+func label(N *node, o PrintOptions) string {
+	return o.Format(N.elem)
+}
+
+// inOrder calls f for every node in the subtree, in order.
+func inOrder(N *node, f func(*node)) {
+	if N != nil {
+		inOrder(N.left, f)
+		f(N)
+		inOrder(N.right, f)
+	}
+}
+
+// sideways draws the subtree with one node per line, the
+// left subtree above and the right subtree below.
+func sideways(buf *bytes.Buffer, N *node, padding int, o PrintOptions) {
+	if N != nil {
+		newp := padding + 3
+		sideways(buf, N.left, newp, o)
+		buf.WriteString(strings.Repeat("-", padding))
+		buf.WriteString(label(N, o))
+		buf.WriteString(" \n")
+		sideways(buf, N.right, newp, o)
+	}
+}
+
+// topDown draws the tree level by level. Every node gets its own
+// column from its in-order position, so no two labels collide.
+func topDown(buf *bytes.Buffer, root *node, o PrintOptions) {
+	labels := map[*node][]rune{}
+	width := 0
+	inOrder(root, func(n *node) {
+		labels[n] = []rune(label(n, o))
+		if len(labels[n]) > width {
+			width = len(labels[n])
+		}
+	})
+
+	cols := map[*node]int{}
+	inOrder(root, func(n *node) {
+		cols[n] = len(cols) * (width + 1)
+	})
+
+	center := func(n *node) int {
+		return cols[n] + (len(labels[n])-1)/2
+	}
+
+	for level := []*node{root}; len(level) > 0; {
+		line := []rune(strings.Repeat(" ", len(cols)*(width+1)))
+		edges := []rune(strings.Repeat(" ", len(line)))
+		next := []*node{}
+
+		for _, n := range level {
+			start, end := cols[n], cols[n]+len(labels[n])
+
+			if n.left != nil {
+				for i := center(n.left) + 1; i < start; i++ {
+					line[i] = '_'
+				}
+				edges[center(n.left)] = '/'
+				next = append(next, n.left)
+			}
+
+			copy(line[start:], labels[n])
+
+			if n.right != nil {
+				for i := end; i < center(n.right); i++ {
+					line[i] = '_'
+				}
+				edges[center(n.right)] = '\\'
+				next = append(next, n.right)
+			}
+		}
+
+		buf.WriteString(strings.TrimRight(string(line), " "))
+		buf.WriteString("\n")
+		if len(next) > 0 {
+			buf.WriteString(strings.TrimRight(string(edges), " "))
+			buf.WriteString("\n")
+		}
+		level = next
+	}
+}
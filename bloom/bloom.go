@@ -0,0 +1,206 @@
+// Package bloom provides Bloom filters: compact sets which may answer
+// that they hold a key they don't (a false positive), but never miss a
+// key they do hold. They make a cheap test before a costly lookup.
+package bloom
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+// A filter has m bits, sets k of them for every key, and counts the
+// keys added.
+//
+// The k positions of a key come from double hashing: the two halves of
+// a 128 bit FNV-1a hash, h1 and h2, give the positions h1 + i*h2 mod m
+// for i from 0 to k-1.
+//
+// e.g. m = 16, k = 2, after Add("a") and Add("b"):
+//     0100 0000 1001 0000
+//
+// The zero value has no bits, so a Filter must come from New,
+// NewWithSize or UnmarshalBinary. Test on a zero Filter returns
+// false, and Add panics.
+type Filter struct {
+	m     uint64
+	k     uint64
+	bits  []uint64
+	count uint64
+}
+
+// maxHashes is the most bits a filter sets for a key. More than that
+// only makes the filter slower, whatever its size.
+const maxHashes = 64
+
+// New is used as a constructor for a Filter that holds the given number
+// of keys with the given false positive rate, e.g. 0.01 for 1%.
+//
+// e.g. myfilter := bloom.New(10000, 0.01)
+//
+func New(capacity int, fpRate float64) *Filter {
+	m, k := optimal(capacity, fpRate)
+	return NewWithSize(m, k)
+}
+
+// NewWithSize is used as a constructor for a Filter with m bits
+// which sets k bits for every key. A k above 64 is lowered to 64.
+//
+// e.g. myfilter := bloom.NewWithSize(1<<20, 7)
+//
+func NewWithSize(m, k uint64) *Filter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	if k > maxHashes {
+		k = maxHashes
+	}
+	return &Filter{m, k, make([]uint64, (m+63)/64), 0}
+}
+
+// Bits returns the number of bits in the filter.
+func (F *Filter) Bits() uint64 {
+	return F.m
+}
+
+// Hashes returns the number of bits set for every key.
+func (F *Filter) Hashes() uint64 {
+	return F.k
+}
+
+// Count returns the number of keys added to the filter.
+// A key added twice is counted twice.
+//
+// e.g. (a, b).Count() => 2
+//
+func (F *Filter) Count() uint64 {
+	return F.count
+}
+
+// Add puts the key in the filter.
+//
+// e.g. ().Add([]byte("a")) => (a)
+//
+func (F *Filter) Add(key []byte) {
+	if F.m == 0 {
+		panic("Filter has no bits, use New or NewWithSize.")
+	}
+
+	h1, h2 := hash(key)
+	for i := uint64(0); i < F.k; i++ {
+		p := (h1 + i*h2) % F.m
+		F.bits[p/64] |= 1 << (p % 64)
+	}
+	F.count++
+}
+
+// Test returns false if the key is not in the filter, and
+// true if it may be.
+//
+// e.g. (a).Test([]byte("a")) => true
+//      (a).Test([]byte("b")) => false, most of the time
+//
+func (F *Filter) Test(key []byte) bool {
+	if F.m == 0 {
+		return false
+	}
+
+	h1, h2 := hash(key)
+	for i := uint64(0); i < F.k; i++ {
+		p := (h1 + i*h2) % F.m
+		if F.bits[p/64]&(1<<(p%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestAndAdd puts the key in the filter, and returns what
+// Test would have returned before.
+//
+// e.g. ().TestAndAdd([]byte("a")) => false
+//      (a).TestAndAdd([]byte("a")) => true
+//
+func (F *Filter) TestAndAdd(key []byte) bool {
+	found := F.Test(key)
+	F.Add(key)
+	return found
+}
+
+// AddString is Add for a string key.
+func (F *Filter) AddString(key string) {
+	F.Add([]byte(key))
+}
+
+// TestString is Test for a string key.
+func (F *Filter) TestString(key string) bool {
+	return F.Test([]byte(key))
+}
+
+// Union adds every key of the other filter to the filter. Both
+// filters must have the same number of bits and hashes.
+//
+// e.g. (a).Union((b)) => (a, b)
+//
+func (F *Filter) Union(other *Filter) error {
+	if F.m != other.m || F.k != other.k {
+		return errors.New("Filters have different sizes.")
+	}
+	for i, w := range other.bits {
+		F.bits[i] |= w
+	}
+	F.count += other.count
+	return nil
+}
+
+// FalsePositiveRate estimates the chance that Test returns true
+// for a key that was never added, given the keys added so far.
+//
+// e.g. New(1000, 0.01) with 1000 keys added => about 0.01
+//
+func (F *Filter) FalsePositiveRate() float64 {
+	return estimate(F.m, F.k, F.count)
+}
+
+// optimal returns the number of bits and hashes that hold the
+// given number of keys with the given false positive rate.
+func optimal(capacity int, fpRate float64) (uint64, uint64) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+
+	n := float64(capacity)
+	m := math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / n * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+	return uint64(m), uint64(k)
+}
+
+// estimate returns the false positive rate of a filter with
+// m bits and k hashes holding n keys: (1 - e^(-kn/m))^k.
+func estimate(m, k, n uint64) float64 {
+	return math.Pow(1-math.Exp(-float64(k)*float64(n)/float64(m)), float64(k))
+}
+
+// hash returns the two halves of the 128 bit FNV-1a hash of the key.
+// The second half is made odd, so it never steps in place.
+func hash(key []byte) (uint64, uint64) {
+	h := fnv.New128a()
+	h.Write(key)
+	sum := h.Sum(nil)
+
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[8+i])
+	}
+	return h1, h2 | 1
+}
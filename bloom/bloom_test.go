@@ -0,0 +1,94 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	f := New(1000, 0.01)
+
+	/* About 9.6 bits and 7 hashes per key */
+	if f.Bits() < 9000 || f.Bits() > 10000 || f.Hashes() != 7 {
+		t.Errorf("New should size the filter for the rate, got %d bits and %d hashes.", f.Bits(), f.Hashes())
+	}
+	if f.Count() != 0 || f.TestString("a") {
+		t.Errorf("A new filter should be empty.")
+	}
+
+	if g := New(0, 2); g.Bits() < 1 || g.Hashes() < 1 {
+		t.Errorf("New should survive silly arguments.")
+	}
+}
+
+func TestAddTest(t *testing.T) {
+	f := New(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.AddString(fmt.Sprint("key", i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !f.TestString(fmt.Sprint("key", i)) {
+			t.Fatalf("Test should never miss an added key.")
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.TestString(fmt.Sprint("other", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("Too many false positives: %d in 10000.", falsePositives)
+	}
+	if rate := f.FalsePositiveRate(); rate < 0.005 || rate > 0.02 {
+		t.Errorf("FalsePositiveRate should be near 0.01, got %v.", rate)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var f Filter
+	var c CountingFilter
+
+	if f.Test([]byte("a")) || c.Test([]byte("a")) || c.Remove([]byte("a")) == nil {
+		t.Errorf("A zero filter should hold nothing.")
+	}
+	for _, add := range []func(){
+		func() { f.Add([]byte("a")) },
+		func() { c.Add([]byte("a")) },
+	} {
+		func() {
+			defer func() {
+				if r, ok := recover().(string); !ok || r == "" {
+					t.Errorf("Add should panic clearly on a zero filter, got %v.", r)
+				}
+			}()
+			add()
+		}()
+	}
+}
+
+func TestTestAndAdd(t *testing.T) {
+	f := New(100, 0.01)
+
+	if f.TestAndAdd([]byte("a")) {
+		t.Errorf("TestAndAdd should report a new key as missing.")
+	}
+	if !f.TestAndAdd([]byte("a")) || f.Count() != 2 {
+		t.Errorf("TestAndAdd should add the key.")
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a, b := New(100, 0.01), New(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+
+	if a.Union(b) != nil || !a.TestString("a") || !a.TestString("b") || a.Count() != 2 {
+		t.Errorf("Union should add the keys of the other filter.")
+	}
+	if a.Union(New(200, 0.01)) == nil {
+		t.Errorf("Union should refuse a filter of another size.")
+	}
+}
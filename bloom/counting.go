@@ -0,0 +1,177 @@
+package bloom
+
+import "errors"
+
+// A counting filter works like a Filter, but keeps a counter instead of
+// a bit, so keys can be removed again. It takes eight times the memory.
+//
+// A counter that reaches 255 stays there, since it can no longer tell
+// how many keys it counts. Removing a key never touches it.
+//
+// Like a Filter, a CountingFilter must come from NewCounting,
+// NewCountingWithSize or UnmarshalBinary. The zero value has no
+// counters: Test returns false, Remove fails and Add panics.
+type CountingFilter struct {
+	m        uint64
+	k        uint64
+	counters []uint8
+	count    uint64
+}
+
+// NewCounting is used as a constructor for a CountingFilter that holds
+// the given number of keys with the given false positive rate.
+//
+// e.g. myfilter := bloom.NewCounting(10000, 0.01)
+//
+func NewCounting(capacity int, fpRate float64) *CountingFilter {
+	m, k := optimal(capacity, fpRate)
+	return NewCountingWithSize(m, k)
+}
+
+// NewCountingWithSize is used as a constructor for a CountingFilter
+// with m counters which counts k of them for every key. A k above 64
+// is lowered to 64.
+//
+// e.g. myfilter := bloom.NewCountingWithSize(1<<20, 7)
+//
+func NewCountingWithSize(m, k uint64) *CountingFilter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	if k > maxHashes {
+		k = maxHashes
+	}
+	return &CountingFilter{m, k, make([]uint8, m), 0}
+}
+
+// Counters returns the number of counters in the filter.
+func (C *CountingFilter) Counters() uint64 {
+	return C.m
+}
+
+// Hashes returns the number of counters used for every key.
+func (C *CountingFilter) Hashes() uint64 {
+	return C.k
+}
+
+// Count returns the number of keys in the filter.
+//
+// e.g. (a, b).Count() => 2
+//
+func (C *CountingFilter) Count() uint64 {
+	return C.count
+}
+
+// Add puts the key in the filter.
+//
+// e.g. ().Add([]byte("a")) => (a)
+//
+func (C *CountingFilter) Add(key []byte) {
+	if C.m == 0 {
+		panic("CountingFilter has no counters, use NewCounting or NewCountingWithSize.")
+	}
+
+	h1, h2 := hash(key)
+	for i := uint64(0); i < C.k; i++ {
+		p := (h1 + i*h2) % C.m
+		if C.counters[p] < 255 {
+			C.counters[p]++
+		}
+	}
+	C.count++
+}
+
+// Test returns false if the key is not in the filter, and
+// true if it may be.
+//
+// e.g. (a).Test([]byte("a")) => true
+//
+func (C *CountingFilter) Test(key []byte) bool {
+	if C.m == 0 {
+		return false
+	}
+
+	h1, h2 := hash(key)
+	for i := uint64(0); i < C.k; i++ {
+		if C.counters[(h1+i*h2)%C.m] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestAndAdd puts the key in the filter, and returns what
+// Test would have returned before.
+//
+// e.g. (a).TestAndAdd([]byte("a")) => true
+//
+func (C *CountingFilter) TestAndAdd(key []byte) bool {
+	found := C.Test(key)
+	C.Add(key)
+	return found
+}
+
+// Remove takes a key out of the filter. Removing a key that was
+// never added may remove other keys, so only remove added keys.
+//
+// e.g. (a, b).Remove([]byte("a")) => (b)
+//
+func (C *CountingFilter) Remove(key []byte) error {
+	if !C.Test(key) {
+		return errors.New("Item not found in Filter.")
+	}
+
+	h1, h2 := hash(key)
+	for i := uint64(0); i < C.k; i++ {
+		p := (h1 + i*h2) % C.m
+		if C.counters[p] < 255 {
+			C.counters[p]--
+		}
+	}
+	C.count--
+	return nil
+}
+
+// AddString is Add for a string key.
+func (C *CountingFilter) AddString(key string) {
+	C.Add([]byte(key))
+}
+
+// TestString is Test for a string key.
+func (C *CountingFilter) TestString(key string) bool {
+	return C.Test([]byte(key))
+}
+
+// RemoveString is Remove for a string key.
+func (C *CountingFilter) RemoveString(key string) error {
+	return C.Remove([]byte(key))
+}
+
+// Union adds every key of the other filter to the filter. Both
+// filters must have the same number of counters and hashes.
+//
+// e.g. (a).Union((b)) => (a, b)
+//
+func (C *CountingFilter) Union(other *CountingFilter) error {
+	if C.m != other.m || C.k != other.k {
+		return errors.New("Filters have different sizes.")
+	}
+	for i, c := range other.counters {
+		if sum := int(C.counters[i]) + int(c); sum < 255 {
+			C.counters[i] = uint8(sum)
+		} else {
+			C.counters[i] = 255
+		}
+	}
+	C.count += other.count
+	return nil
+}
+
+// FalsePositiveRate estimates the chance that Test returns true
+// for a key that is not in the filter.
+func (C *CountingFilter) FalsePositiveRate() float64 {
+	return estimate(C.m, C.k, C.count)
+}
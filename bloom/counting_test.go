@@ -0,0 +1,71 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCountingRemove(t *testing.T) {
+	c := NewCounting(1000, 0.01)
+	for i := 0; i < 500; i++ {
+		c.AddString(fmt.Sprint("key", i))
+	}
+
+	for i := 0; i < 500; i += 2 {
+		if c.RemoveString(fmt.Sprint("key", i)) != nil {
+			t.Fatalf("Remove should remove an added key.")
+		}
+	}
+	if c.Count() != 250 {
+		t.Errorf("Remove should count the removed keys.")
+	}
+
+	for i := 1; i < 500; i += 2 {
+		if !c.TestString(fmt.Sprint("key", i)) {
+			t.Fatalf("Remove should not remove the other keys.")
+		}
+	}
+	removed := 0
+	for i := 0; i < 500; i += 2 {
+		if !c.TestString(fmt.Sprint("key", i)) {
+			removed++
+		}
+	}
+	if removed < 240 {
+		t.Errorf("Removed keys should be gone, only %d of 250 are.", removed)
+	}
+
+	if !c.TestString("never added") && c.RemoveString("never added") == nil {
+		t.Errorf("Remove should refuse a key that is not in the filter.")
+	}
+}
+
+func TestCountingSaturation(t *testing.T) {
+	c := NewCountingWithSize(8, 1)
+	for i := 0; i < 300; i++ {
+		c.AddString("a")
+	}
+	for i := 0; i < 300; i++ {
+		c.RemoveString("a")
+	}
+
+	if !c.TestString("a") {
+		t.Errorf("A saturated counter should never be decremented.")
+	}
+}
+
+func TestCountingUnion(t *testing.T) {
+	a, b := NewCounting(100, 0.01), NewCounting(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+
+	if a.Union(b) != nil || !a.TestString("b") {
+		t.Errorf("Union should add the keys of the other filter.")
+	}
+	if a.RemoveString("b") != nil || a.TestString("b") || !a.TestString("a") {
+		t.Errorf("A key added by Union should be removable.")
+	}
+	if a.Union(NewCountingWithSize(10, 1)) == nil {
+		t.Errorf("Union should refuse a filter of another size.")
+	}
+}
@@ -0,0 +1,104 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The binary formats start with a magic string and a version byte,
+// followed by the size, the number of hashes and the count as big
+// endian uint64s, and then the bits or the counters.
+const (
+	magic         = "GBLM"
+	countingMagic = "GCBF"
+	version       = 1
+	headerSize    = len(magic) + 1 + 3*8
+)
+
+// MarshalBinary encodes the filter. It implements encoding.BinaryMarshaler.
+func (F *Filter) MarshalBinary() ([]byte, error) {
+	buf := header(magic, F.m, F.k, F.count, 8*len(F.bits))
+	for _, w := range F.bits {
+		buf = binary.BigEndian.AppendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary.
+// It implements encoding.BinaryUnmarshaler.
+//
+// e.g. myfilter := new(bloom.Filter)
+//      err := myfilter.UnmarshalBinary(data)
+//
+func (F *Filter) UnmarshalBinary(data []byte) error {
+	m, k, count, body, err := readHeader(magic, data)
+	if err != nil {
+		return err
+	}
+	if m > uint64(len(body))*8 || uint64(len(body)) != (m+63)/64*8 {
+		return errors.New("Corrupt Filter data: size mismatch.")
+	}
+
+	bits := make([]uint64, len(body)/8)
+	for i := range bits {
+		bits[i] = binary.BigEndian.Uint64(body[8*i:])
+	}
+	F.m, F.k, F.bits, F.count = m, k, bits, count
+	return nil
+}
+
+// MarshalBinary encodes the filter. It implements encoding.BinaryMarshaler.
+func (C *CountingFilter) MarshalBinary() ([]byte, error) {
+	buf := header(countingMagic, C.m, C.k, C.count, len(C.counters))
+	return append(buf, C.counters...), nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary.
+// It implements encoding.BinaryUnmarshaler.
+func (C *CountingFilter) UnmarshalBinary(data []byte) error {
+	m, k, count, body, err := readHeader(countingMagic, data)
+	if err != nil {
+		return err
+	}
+	if uint64(len(body)) != m {
+		return errors.New("Corrupt Filter data: size mismatch.")
+	}
+
+	C.m, C.k, C.counters, C.count = m, k, append([]uint8{}, body...), count
+	return nil
+}
+
+// header returns the start of an encoded filter, with room
+// for the given number of bytes to follow.
+func header(mgc string, m, k, count uint64, size int) []byte {
+	buf := make([]byte, 0, headerSize+size)
+	buf = append(buf, mgc...)
+	buf = append(buf, version)
+	buf = binary.BigEndian.AppendUint64(buf, m)
+	buf = binary.BigEndian.AppendUint64(buf, k)
+	return binary.BigEndian.AppendUint64(buf, count)
+}
+
+// readHeader checks the start of an encoded filter, and returns
+// its fields and the rest of the data.
+func readHeader(mgc string, data []byte) (uint64, uint64, uint64, []byte, error) {
+	if len(data) < headerSize || string(data[:len(mgc)]) != mgc {
+		return 0, 0, 0, nil, errors.New("Data is not an encoded Filter.")
+	}
+	if data[len(mgc)] != version {
+		return 0, 0, 0, nil, fmt.Errorf("Unsupported Filter encoding version %d.", data[len(mgc)])
+	}
+
+	fields := data[len(mgc)+1:]
+	m := binary.BigEndian.Uint64(fields)
+	k := binary.BigEndian.Uint64(fields[8:])
+	count := binary.BigEndian.Uint64(fields[16:])
+	if m < 1 || k < 1 {
+		return 0, 0, 0, nil, errors.New("Corrupt Filter data: empty filter.")
+	}
+	if k > maxHashes {
+		return 0, 0, 0, nil, errors.New("Corrupt Filter data: too many hashes.")
+	}
+	return m, k, count, data[headerSize:], nil
+}
@@ -0,0 +1,86 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	f := New(100, 0.01)
+	f.AddString("a")
+	f.AddString("b")
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	g := new(Filter)
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if g.Bits() != f.Bits() || g.Hashes() != f.Hashes() || g.Count() != 2 {
+		t.Errorf("UnmarshalBinary should restore the size and count.")
+	}
+	if !g.TestString("a") || !g.TestString("b") || g.TestString("c") != f.TestString("c") {
+		t.Errorf("UnmarshalBinary should restore the keys.")
+	}
+}
+
+func TestMarshalBinaryCounting(t *testing.T) {
+	c := NewCounting(100, 0.01)
+	c.AddString("a")
+
+	data, _ := c.MarshalBinary()
+	d := new(CountingFilter)
+	if err := d.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if !d.TestString("a") || d.RemoveString("a") != nil || d.TestString("a") {
+		t.Errorf("UnmarshalBinary should restore the counters.")
+	}
+
+	if new(Filter).UnmarshalBinary(data) == nil {
+		t.Errorf("A Filter should refuse a CountingFilter encoding.")
+	}
+}
+
+func TestUnmarshalBinaryCorrupt(t *testing.T) {
+	data, _ := New(100, 0.01).MarshalBinary()
+
+	bad := append([]byte{}, data...)
+	bad[4] = 9
+	if new(Filter).UnmarshalBinary(bad) == nil {
+		t.Errorf("UnmarshalBinary should refuse an unknown version.")
+	}
+	if new(Filter).UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Errorf("UnmarshalBinary should refuse truncated data.")
+	}
+	if new(Filter).UnmarshalBinary([]byte("nonsense")) == nil {
+		t.Errorf("UnmarshalBinary should refuse garbage.")
+	}
+}
+
+func TestUnmarshalBinaryHostile(t *testing.T) {
+	plain, _ := New(100, 0.01).MarshalBinary()
+	counting, _ := NewCounting(100, 0.01).MarshalBinary()
+
+	for _, data := range [][]byte{plain, counting} {
+		/* k is the second field after the magic and the version */
+		bad := append([]byte{}, data...)
+		binary.BigEndian.PutUint64(bad[5+8:], 1<<63)
+		if new(Filter).UnmarshalBinary(bad) == nil || new(CountingFilter).UnmarshalBinary(bad) == nil {
+			t.Errorf("UnmarshalBinary should refuse too many hashes.")
+		}
+
+		bad = append([]byte{}, data...)
+		binary.BigEndian.PutUint64(bad[5:], binary.BigEndian.Uint64(data[5:])+512)
+		if new(Filter).UnmarshalBinary(bad) == nil || new(CountingFilter).UnmarshalBinary(bad) == nil {
+			t.Errorf("UnmarshalBinary should refuse a size that doesn't match the data.")
+		}
+	}
+
+	if NewWithSize(100, 1000).Hashes() != maxHashes || NewCountingWithSize(100, 1000).k != maxHashes {
+		t.Errorf("The constructors should lower k to the most UnmarshalBinary accepts.")
+	}
+}
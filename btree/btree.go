@@ -0,0 +1,625 @@
+// Package btree provides an in-memory B-tree. It keeps many
+// elements in every node, which is kinder to the cache and the
+// garbage collector than one node per element.
+package btree
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// A btree has a degree, a user defined function which is used to
+// compare the elements, a size, a pointer to the root node, and the
+// copy-on-write context of the tree.
+//
+// Every node but the root holds between degree-1 and 2*degree-1
+// elements, and every leaf is on the same level.
+//
+// e.g. degree 2:
+//              (4)
+//            /     \
+//       (1 2 3)   (5 6)
+//
+type BTree struct {
+	degree int
+	less   LessFunc
+	size   int
+	root   *node
+	cow    *copyOnWrite
+}
+
+// The btree is made up of nodes with sorted elements, pointers
+// to the children between them (none for a leaf), and the
+// copy-on-write context that owns the node.
+type node struct {
+	elems    []Elem
+	children []*node
+	cow      *copyOnWrite
+}
+
+// copyOnWrite identifies the tree that may modify a node. A tree only
+// modifies the nodes it owns, and copies any other node first. Clone
+// gives both trees a new context, so the nodes they share are copied
+// on their next write. It can't be zero sized, since every context
+// must have an address of its own.
+type copyOnWrite struct {
+	id int
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// LessFunc is used as a user function to compare elements in the tree.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// New is used as a constructor for the BTree struct. A degree of
+// 32 or so suits most uses, a degree below 2 is raised to 2.
+//
+// e.g. mytree := btree.New(32, intLess)
+//
+func New(degree int, lf LessFunc) *BTree {
+	if degree < 2 {
+		degree = 2
+	}
+	return &BTree{degree, lf, 0, nil, &copyOnWrite{}}
+}
+
+// FromSorted builds a tree from a go slice whose elements are sorted
+// in strictly ascending order. It runs in linear time.
+//
+// e.g. mytree, err := btree.FromSorted(32, intLess, []int{1, 2, 3})
+//
+func FromSorted(degree int, lf LessFunc, slc interface{}) (*BTree, error) {
+	v := reflect.ValueOf(slc)
+	elems := make([]Elem, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elems[i] = v.Index(i).Interface()
+		if i > 0 && !lf(elems[i-1], elems[i]) {
+			return nil, errors.New("Slice is not sorted.")
+		}
+	}
+
+	T := New(degree, lf)
+	T.load(elems)
+	return T, nil
+}
+
+// FromSlice builds a tree from a go slice in any order.
+// Like Add, a later element replaces an earlier equal one.
+//
+// e.g. mytree := btree.FromSlice(32, intLess, []int{3, 1, 2})
+//
+func FromSlice(degree int, lf LessFunc, slc interface{}) *BTree {
+	v := reflect.ValueOf(slc)
+	elems := make([]Elem, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elems[i] = v.Index(i).Interface()
+	}
+
+	sort.SliceStable(elems, func(i, j int) bool {
+		return lf(elems[i], elems[j])
+	})
+
+	/* Keep the last element of every run of equal elements */
+	uniq := elems[:0]
+	for i, e := range elems {
+		if i+1 < len(elems) && !lf(e, elems[i+1]) {
+			continue
+		}
+		uniq = append(uniq, e)
+	}
+
+	T := New(degree, lf)
+	T.load(uniq)
+	return T
+}
+
+// Size returns the size of the tree.
+//
+// e.g. (2 (1) (3)).Size() => 3
+//
+func (T *BTree) Size() int {
+	return T.size
+}
+
+// Empty returns true if the tree is empty.
+//
+// e.g. ().Empty() => true
+//
+func (T *BTree) Empty() bool {
+	return T.size == 0
+}
+
+// Clone returns a copy of the tree in O(1). The two trees share their
+// nodes until either is modified, then only the nodes on the modified
+// path are copied.
+//
+// e.g. snapshot := mytree.Clone()
+//
+func (T *BTree) Clone() *BTree {
+	cow1, cow2 := *T.cow, *T.cow
+	out := *T
+	T.cow = &cow1
+	out.cow = &cow2
+	return &out
+}
+
+// Add inserts an element into the tree. An equal element
+// already in the tree is replaced, and an error is returned.
+//
+// e.g. (2 (1) ()).Add(3) => (2 (1) (3))
+//
+func (T *BTree) Add(E Elem) error {
+	if T.root == nil {
+		T.root = &node{[]Elem{E}, nil, T.cow}
+		T.size++
+		return nil
+	}
+
+	T.root = T.root.mutableFor(T.cow)
+	if len(T.root.elems) >= T.maxElems() {
+		elem, second := T.root.split(T.maxElems() / 2)
+		first := T.root
+		T.root = &node{[]Elem{elem}, []*node{first, second}, T.cow}
+	}
+
+	if T.insert(T.root, E) {
+		return errors.New("Item already exists in Tree.")
+	}
+	T.size++
+	return nil
+}
+
+// Remove deletes an element from the tree.
+//
+// e.g. (2 (1) (3)).Remove(2) => (3 (1) ())
+//
+func (T *BTree) Remove(E Elem) error {
+	if T.root == nil || !T.Contains(E) {
+		return errors.New("Item not found in Tree.")
+	}
+
+	T.root = T.root.mutableFor(T.cow)
+	T.remove(T.root, E, removeElem)
+
+	if len(T.root.elems) == 0 {
+		if len(T.root.children) > 0 {
+			T.root = T.root.children[0]
+		} else {
+			T.root = nil
+		}
+	}
+	T.size--
+	return nil
+}
+
+// Contains returns true if the given element exists
+// within the tree.
+//
+// e.g. (2 (1) (3)).Contains(1) => true
+//
+func (T *BTree) Contains(E Elem) bool {
+	for n := T.root; n != nil; {
+		i, found := T.find(n, E)
+		if found {
+			return true
+		}
+		if len(n.children) == 0 {
+			return false
+		}
+		n = n.children[i]
+	}
+	return false
+}
+
+// First returns the smallest element in the tree.
+//
+// e.g. (2 (1) (3)).First() => 1
+//
+func (T *BTree) First() Elem {
+	if T.root == nil {
+		return nil
+	}
+	n := T.root
+	for len(n.children) > 0 {
+		n = n.children[0]
+	}
+	return n.elems[0]
+}
+
+// Last returns the biggest element in the tree.
+//
+// e.g. (2 (1) (3)).Last() => 3
+//
+func (T *BTree) Last() Elem {
+	if T.root == nil {
+		return nil
+	}
+	n := T.root
+	for len(n.children) > 0 {
+		n = n.children[len(n.children)-1]
+	}
+	return n.elems[len(n.elems)-1]
+}
+
+// InOrder returns an iterator over the tree, smallest
+// element first. It walks a clone of the tree, so the tree
+// may be modified while the iterator is used.
+//
+// e.g. for x := range (2 (1) (3)).InOrder() { x } => 1, 2, 3
+//
+func (T *BTree) InOrder() chan Elem {
+	ch := make(chan Elem, T.size)
+	snapshot := T.Clone()
+	go func() {
+		snapshot.ascend(snapshot.root, nil, nil, func(E Elem) {
+			ch <- E
+		})
+		close(ch)
+	}()
+	return ch
+}
+
+// Range returns an iterator over the elements that are equal
+// or bigger than lo, and less than hi.
+//
+// e.g. for x := range (2 (1) (3 () (4))).Range(2, 4) { x } => 2, 3
+//
+func (T *BTree) Range(lo, hi Elem) chan Elem {
+	res := []Elem{}
+	T.ascend(T.root, lo, hi, func(E Elem) {
+		res = append(res, E)
+	})
+
+	ch := make(chan Elem, len(res))
+	for _, E := range res {
+		ch <- E
+	}
+	close(ch)
+	return ch
+}
+
+// maxElems is the most elements a node can hold.
+func (T *BTree) maxElems() int {
+	return 2*T.degree - 1
+}
+
+// minElems is the least elements a node, but the root, can hold.
+func (T *BTree) minElems() int {
+	return T.degree - 1
+}
+
+// find returns the position of the element in the node, and true if it
+// is there. Otherwise it returns the child the element belongs to.
+func (T *BTree) find(n *node, E Elem) (int, bool) {
+	i := sort.Search(len(n.elems), func(i int) bool {
+		return T.less(E, n.elems[i])
+	})
+	if i > 0 && !T.less(n.elems[i-1], E) {
+		return i - 1, true
+	}
+	return i, false
+}
+
+// ascend calls f, in order, for the elements in the subtree that are
+// equal or bigger than lo and less than hi. A nil bound is left open.
+// It returns false once an element reaches hi.
+func (T *BTree) ascend(n *node, lo, hi Elem, f func(Elem)) bool {
+	if n == nil {
+		return true
+	}
+
+	i := 0
+	if lo != nil {
+		i = sort.Search(len(n.elems), func(i int) bool {
+			return !T.less(n.elems[i], lo)
+		})
+	}
+
+	for ; i < len(n.elems); i++ {
+		if len(n.children) > 0 && !T.ascend(n.children[i], lo, hi, f) {
+			return false
+		}
+		if hi != nil && !T.less(n.elems[i], hi) {
+			return false
+		}
+		f(n.elems[i])
+	}
+
+	if len(n.children) > 0 {
+		return T.ascend(n.children[len(n.children)-1], lo, hi, f)
+	}
+	return true
+}
+
+// insert adds the element to the subtree, splitting full nodes on the
+// way down. It returns true if an equal element was replaced.
+func (T *BTree) insert(n *node, E Elem) bool {
+	i, found := T.find(n, E)
+	if found {
+		n.elems[i] = E
+		return true
+	}
+
+	if len(n.children) == 0 {
+		n.elems = insertElem(n.elems, i, E)
+		return false
+	}
+
+	if T.maybeSplitChild(n, i) {
+		switch inTree := n.elems[i]; {
+		case T.less(E, inTree):
+			// The element belongs in the first half
+		case T.less(inTree, E):
+			i++
+		default:
+			n.elems[i] = E
+			return true
+		}
+	}
+
+	return T.insert(n.mutableChild(i), E)
+}
+
+// maybeSplitChild splits the i:th child of the node if it is full.
+// It returns true if the child was split.
+func (T *BTree) maybeSplitChild(n *node, i int) bool {
+	if len(n.children[i].elems) < T.maxElems() {
+		return false
+	}
+
+	first := n.mutableChild(i)
+	elem, second := first.split(T.maxElems() / 2)
+	n.elems = insertElem(n.elems, i, elem)
+	n.children = insertChild(n.children, i+1, second)
+	return true
+}
+
+// What remove should remove from the subtree.
+type toRemove int
+
+const (
+	removeElem toRemove = iota // the given element
+	removeMax                  // the biggest element
+)
+
+// remove deletes an element from the subtree and returns it. Before
+// moving down to a child, it makes sure the child has an element to
+// spare, so the removal never leaves a node too small.
+func (T *BTree) remove(n *node, E Elem, typ toRemove) Elem {
+	var i int
+	var found bool
+
+	switch typ {
+	case removeMax:
+		if len(n.children) == 0 {
+			last := n.elems[len(n.elems)-1]
+			n.elems = removeElemAt(n.elems, len(n.elems)-1)
+			return last
+		}
+		i = len(n.elems)
+	case removeElem:
+		i, found = T.find(n, E)
+		if len(n.children) == 0 {
+			out := n.elems[i]
+			n.elems = removeElemAt(n.elems, i)
+			return out
+		}
+	}
+
+	if len(n.children[i].elems) <= T.minElems() {
+		T.growChild(n, i)
+		return T.remove(n, E, typ)
+	}
+
+	child := n.mutableChild(i)
+	if found {
+		/* Replace the element with its predecessor */
+		out := n.elems[i]
+		n.elems[i] = T.remove(child, nil, removeMax)
+		return out
+	}
+	return T.remove(child, E, typ)
+}
+
+// growChild gives the i:th child of the node another element, by
+// stealing one from a sibling or by merging it with a sibling.
+func (T *BTree) growChild(n *node, i int) {
+	switch {
+	case i > 0 && len(n.children[i-1].elems) > T.minElems():
+		/* Steal from the left sibling */
+		child := n.mutableChild(i)
+		from := n.mutableChild(i - 1)
+
+		stolen := from.elems[len(from.elems)-1]
+		from.elems = removeElemAt(from.elems, len(from.elems)-1)
+		child.elems = insertElem(child.elems, 0, n.elems[i-1])
+		n.elems[i-1] = stolen
+
+		if len(from.children) > 0 {
+			c := from.children[len(from.children)-1]
+			from.children = removeChildAt(from.children, len(from.children)-1)
+			child.children = insertChild(child.children, 0, c)
+		}
+
+	case i < len(n.elems) && len(n.children[i+1].elems) > T.minElems():
+		/* Steal from the right sibling */
+		child := n.mutableChild(i)
+		from := n.mutableChild(i + 1)
+
+		stolen := from.elems[0]
+		from.elems = removeElemAt(from.elems, 0)
+		child.elems = append(child.elems, n.elems[i])
+		n.elems[i] = stolen
+
+		if len(from.children) > 0 {
+			c := from.children[0]
+			from.children = removeChildAt(from.children, 0)
+			child.children = append(child.children, c)
+		}
+
+	default:
+		/* Merge with a sibling */
+		if i >= len(n.elems) {
+			i--
+		}
+		child := n.mutableChild(i)
+		merge := n.children[i+1]
+
+		child.elems = append(child.elems, n.elems[i])
+		child.elems = append(child.elems, merge.elems...)
+		child.children = append(child.children, merge.children...)
+
+		n.elems = removeElemAt(n.elems, i)
+		n.children = removeChildAt(n.children, i+1)
+	}
+}
+
+// load replaces the tree with a tree of the given sorted elements.
+func (T *BTree) load(elems []Elem) {
+	T.size = len(elems)
+	T.root = nil
+	if len(elems) == 0 {
+		return
+	}
+
+	height := 0
+	for len(elems) > T.capacity(height) {
+		height++
+	}
+	T.root = T.build(elems, height, true)
+}
+
+// capacity returns the most elements a subtree of the given
+// height can hold: (2*degree)^(height+1) - 1.
+func (T *BTree) capacity(height int) int {
+	c := 1
+	for i := 0; i <= height; i++ {
+		c *= 2 * T.degree
+	}
+	return c - 1
+}
+
+// build creates a subtree of the given height from sorted elements.
+// The elements are spread evenly over as few children as will hold
+// them, but never fewer than a node needs. Every child then holds at
+// least the minimum for its height, so the result is a valid tree.
+func (T *BTree) build(elems []Elem, height int, root bool) *node {
+	n := &node{cow: T.cow}
+	if height == 0 {
+		n.elems = append(make([]Elem, 0, T.maxElems()), elems...)
+		return n
+	}
+
+	/* Each child, together with the separator after it, takes a share */
+	total := len(elems) + 1
+	share := T.capacity(height-1) + 1
+	count := (total + share - 1) / share
+	if root && count < 2 {
+		count = 2
+	}
+	if !root && count < T.degree {
+		count = T.degree
+	}
+
+	start := 0
+	for k := 0; k < count; k++ {
+		part := total / count
+		if k < total%count {
+			part++
+		}
+
+		n.children = append(n.children, T.build(elems[start:start+part-1], height-1, false))
+		start += part - 1
+
+		if k < count-1 {
+			n.elems = append(n.elems, elems[start])
+			start++
+		}
+	}
+	return n
+}
+
+// mutableFor returns the node if it is owned by the given context,
+// or a copy owned by the context.
+func (N *node) mutableFor(cow *copyOnWrite) *node {
+	if N.cow == cow {
+		return N
+	}
+
+	out := &node{cow: cow}
+	out.elems = append(make([]Elem, 0, cap(N.elems)), N.elems...)
+	if len(N.children) > 0 {
+		out.children = append(make([]*node, 0, cap(N.children)), N.children...)
+	}
+	return out
+}
+
+// mutableChild makes sure the i:th child is owned by the
+// same context as the node, and returns it.
+func (N *node) mutableChild(i int) *node {
+	c := N.children[i].mutableFor(N.cow)
+	N.children[i] = c
+	return c
+}
+
+// split cuts the node at the given position. It returns the element
+// at the position and a new node with everything after it.
+func (N *node) split(i int) (Elem, *node) {
+	elem := N.elems[i]
+
+	next := &node{cow: N.cow}
+	next.elems = append(next.elems, N.elems[i+1:]...)
+	for j := i; j < len(N.elems); j++ {
+		N.elems[j] = nil
+	}
+	N.elems = N.elems[:i]
+
+	if len(N.children) > 0 {
+		next.children = append(next.children, N.children[i+1:]...)
+		for j := i + 1; j < len(N.children); j++ {
+			N.children[j] = nil
+		}
+		N.children = N.children[:i+1]
+	}
+
+	return elem, next
+}
+
+// insertElem inserts the element at the given position.
+func insertElem(s []Elem, i int, E Elem) []Elem {
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = E
+	return s
+}
+
+// removeElemAt removes the element at the given position.
+func removeElemAt(s []Elem, i int) []Elem {
+	copy(s[i:], s[i+1:])
+	s[len(s)-1] = nil
+	return s[:len(s)-1]
+}
+
+// insertChild inserts the child at the given position.
+func insertChild(s []*node, i int, n *node) []*node {
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = n
+	return s
+}
+
+// removeChildAt removes the child at the given position.
+func removeChildAt(s []*node, i int) []*node {
+	copy(s[i:], s[i+1:])
+	s[len(s)-1] = nil
+	return s[:len(s)-1]
+}
@@ -0,0 +1,347 @@
+package btree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/emnl/goods/redblacktree"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// checkTree reports every broken btree invariant.
+func checkTree(t *testing.T, tree *BTree) {
+	size, _ := checkNode(t, tree, tree.root, true)
+	if size != tree.size {
+		t.Errorf("Size is %d but the tree holds %d elements.", tree.size, size)
+	}
+}
+
+// checkNode checks the subtree and returns its size and height.
+func checkNode(t *testing.T, tree *BTree, n *node, root bool) (int, int) {
+	if n == nil {
+		return 0, 0
+	}
+	if len(n.elems) > tree.maxElems() || (!root && len(n.elems) < tree.minElems()) {
+		t.Errorf("Node %v holds a wrong number of elements.", n.elems)
+	}
+	for i := 1; i < len(n.elems); i++ {
+		if !tree.less(n.elems[i-1], n.elems[i]) {
+			t.Errorf("Node %v is out of order.", n.elems)
+		}
+	}
+	if len(n.children) == 0 {
+		return len(n.elems), 0
+	}
+	if len(n.children) != len(n.elems)+1 {
+		t.Errorf("Node %v has %d children.", n.elems, len(n.children))
+	}
+
+	size, height := len(n.elems), -1
+	for i, c := range n.children {
+		if i > 0 && !tree.less(n.elems[i-1], c.elems[0]) {
+			t.Errorf("Child %d of %v is out of order.", i, n.elems)
+		}
+		if i < len(n.elems) && !tree.less(c.elems[len(c.elems)-1], n.elems[i]) {
+			t.Errorf("Child %d of %v is out of order.", i, n.elems)
+		}
+		s, h := checkNode(t, tree, c, false)
+		if height != -1 && h != height {
+			t.Errorf("Leaves below %v are on different levels.", n.elems)
+		}
+		size, height = size+s, h
+	}
+	return size, height + 1
+}
+
+// toSlice returns the elements of the tree in order.
+func toSlice(tree *BTree) []int {
+	res := []int{}
+	for x := range tree.InOrder() {
+		res = append(res, x.(int))
+	}
+	return res
+}
+
+func TestNew(t *testing.T) {
+	tree := New(3, intLess)
+
+	if tree.Size() != 0 || !tree.Empty() || tree.First() != nil || tree.Last() != nil {
+		t.Errorf("New constructor is broken.")
+	}
+	if New(0, intLess).degree != 2 {
+		t.Errorf("New should raise a small degree to 2.")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	for degree := 2; degree <= 5; degree++ {
+		tree := New(degree, intLess)
+		for _, x := range rand.New(rand.NewSource(1)).Perm(500) {
+			if tree.Add(x) != nil {
+				t.Errorf("Add should accept a new element.")
+			}
+		}
+		checkTree(t, tree)
+
+		if tree.Add(250) == nil {
+			t.Errorf("Add should refuse an element that already exists.")
+		}
+		if tree.Size() != 500 || tree.First() != 0 || tree.Last() != 499 {
+			t.Errorf("Add should add elements.")
+		}
+		for i, x := range toSlice(tree) {
+			if i != x {
+				t.Errorf("InOrder should return the elements in order.")
+				break
+			}
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	for degree := 2; degree <= 5; degree++ {
+		r := rand.New(rand.NewSource(int64(degree)))
+		tree := New(degree, intLess)
+		for _, x := range r.Perm(500) {
+			tree.Add(x)
+		}
+
+		if tree.Remove(500) == nil {
+			t.Errorf("Remove should refuse an element that doesn't exist.")
+		}
+
+		for i, x := range r.Perm(500) {
+			if tree.Remove(x) != nil {
+				t.Errorf("Remove should remove an existing element.")
+			}
+			if tree.Contains(x) || tree.Size() != 499-i {
+				t.Errorf("Remove should remove elements.")
+			}
+			if i%50 == 0 {
+				checkTree(t, tree)
+			}
+		}
+
+		if !tree.Empty() || tree.root != nil {
+			t.Errorf("Remove should leave an empty tree.")
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tree := FromSlice(2, intLess, []int{5, 3, 8, 1})
+
+	if !tree.Contains(1) || !tree.Contains(8) || tree.Contains(4) {
+		t.Errorf("Contains is broken.")
+	}
+}
+
+func TestRange(t *testing.T) {
+	tree := New(2, intLess)
+	for i := 0; i < 100; i += 2 {
+		tree.Add(i)
+	}
+
+	res := []int{}
+	for x := range tree.Range(11, 20) {
+		res = append(res, x.(int))
+	}
+	if len(res) != 4 || res[0] != 12 || res[3] != 18 {
+		t.Errorf("Range should return [lo, hi), got %v.", res)
+	}
+
+	if len(tree.Range(20, 20)) != 0 || len(tree.Range(200, 300)) != 0 {
+		t.Errorf("Range should be empty when nothing is in range.")
+	}
+	if len(tree.Range(-10, 200)) != 50 {
+		t.Errorf("Range should return everything within range.")
+	}
+}
+
+func TestFromSorted(t *testing.T) {
+	for degree := 2; degree <= 4; degree++ {
+		for n := 0; n < 300; n++ {
+			slc := make([]int, n)
+			for i := range slc {
+				slc[i] = i
+			}
+
+			tree, err := FromSorted(degree, intLess, slc)
+			if err != nil {
+				t.Fatalf("FromSorted should accept a sorted slice.")
+			}
+			checkTree(t, tree)
+			if res := toSlice(tree); len(res) != n {
+				t.Errorf("FromSorted should hold every element.")
+			}
+
+			tree.Add(n)
+			tree.Remove(0)
+			checkTree(t, tree)
+		}
+	}
+
+	if _, err := FromSorted(2, intLess, []int{1, 3, 2}); err == nil {
+		t.Errorf("FromSorted should refuse an unsorted slice.")
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	type pair struct{ key, val int }
+	less := func(a, b interface{}) bool { return a.(pair).key < b.(pair).key }
+
+	tree := FromSlice(2, less, []pair{{3, 0}, {1, 0}, {3, 1}, {2, 0}, {1, 1}})
+
+	if tree.Size() != 3 {
+		t.Errorf("FromSlice should drop duplicates.")
+	}
+	for x := range tree.InOrder() {
+		if x.(pair).key != 2 && x.(pair).val != 1 {
+			t.Errorf("FromSlice should keep the last of equal elements.")
+		}
+	}
+	checkTree(t, tree)
+}
+
+func TestClone(t *testing.T) {
+	tree := New(2, intLess)
+	for i := 0; i < 100; i++ {
+		tree.Add(i)
+	}
+
+	clone := tree.Clone()
+	for i := 0; i < 100; i += 2 {
+		tree.Remove(i)
+	}
+	for i := 100; i < 150; i++ {
+		clone.Add(i)
+	}
+
+	checkTree(t, tree)
+	checkTree(t, clone)
+	if tree.Size() != 50 || tree.Contains(0) || tree.Contains(120) {
+		t.Errorf("Changes to a clone should not show in the tree.")
+	}
+	if clone.Size() != 150 || !clone.Contains(0) || !clone.Contains(120) {
+		t.Errorf("Changes to the tree should not show in a clone.")
+	}
+}
+
+func TestInOrderWhileModified(t *testing.T) {
+	tree := FromSlice(2, intLess, []int{1, 2, 3, 4, 5})
+
+	ch := tree.InOrder()
+	tree.Remove(3)
+	tree.Add(6)
+
+	res := []int{}
+	for x := range ch {
+		res = append(res, x.(int))
+	}
+	if len(res) != 5 || res[2] != 3 {
+		t.Errorf("InOrder should iterate the tree as it was, got %v.", res)
+	}
+}
+
+const benchSize = 100000
+
+func BenchmarkBTreeAdd(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := New(32, intLess)
+		for _, x := range perm {
+			tree.Add(x)
+		}
+	}
+}
+
+func BenchmarkRedBlackTreeAdd(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := redblacktree.New(intLess)
+		for _, x := range perm {
+			tree.Add(x)
+		}
+	}
+}
+
+func BenchmarkBTreeContains(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	tree := New(32, intLess)
+	for _, x := range perm {
+		tree.Add(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Contains(perm[i%benchSize])
+	}
+}
+
+func BenchmarkRedBlackTreeContains(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	tree := redblacktree.New(intLess)
+	for _, x := range perm {
+		tree.Add(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Contains(perm[i%benchSize])
+	}
+}
+
+func BenchmarkBTreeRemove(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	tree := New(32, intLess)
+	for _, x := range perm {
+		tree.Add(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := perm[i%benchSize]
+		tree.Remove(x)
+		tree.Add(x)
+	}
+}
+
+func BenchmarkRedBlackTreeRemove(b *testing.B) {
+	perm := rand.New(rand.NewSource(1)).Perm(benchSize)
+	tree := redblacktree.New(intLess)
+	for _, x := range perm {
+		tree.Add(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := perm[i%benchSize]
+		tree.Remove(x)
+		tree.Add(x)
+	}
+}
+
+func BenchmarkBTreeInOrder(b *testing.B) {
+	tree := New(32, intLess)
+	for i := 0; i < benchSize; i++ {
+		tree.Add(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range tree.InOrder() {
+		}
+	}
+}
+
+func BenchmarkRedBlackTreeInOrder(b *testing.B) {
+	tree := redblacktree.New(intLess)
+	for i := 0; i < benchSize; i++ {
+		tree.Add(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range tree.InOrder() {
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Package delayqueue provides a queue which holds each element until
+// its deadline. The elements come out in the order of their deadlines,
+// and elements with the same deadline in the order they were offered.
+package delayqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/emnl/goods/heap"
+)
+
+// A delay queue has a heap of items ordered by their deadline, the
+// number of items offered so far, which keeps equal deadlines in
+// order, and a clock. The wake channel is closed, and replaced, when
+// an item with an earlier deadline than any other is offered, so the
+// goroutines waiting in Take can look again.
+//
+// The delay queue is thread-safe.
+type DelayQueue struct {
+	mu    sync.Mutex
+	items *heap.Heap
+	seq   uint64
+	clock Clock
+	wake  chan struct{}
+}
+
+// An element, its deadline, and when it was offered.
+type item struct {
+	value   Elem
+	readyAt time.Time
+	seq     uint64
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// Clock tells the time to the queue. Tests may use a fake one to
+// move time forward at will.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer which fires once the duration
+	// has passed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer made by a Clock. Take stops the timers it no
+// longer waits for, so they don't pile up.
+type Timer interface {
+	// C returns the channel which receives the time when
+	// the timer fires.
+	C() <-chan time.Time
+
+	// Stop keeps the timer from firing. It returns false if
+	// the timer already fired or was stopped.
+	Stop() bool
+}
+
+// realClock is the Clock of the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                 { return time.Now() }
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+// realTimer is the Timer of the time package.
+type realTimer struct{ t *time.Timer }
+
+func (T realTimer) C() <-chan time.Time { return T.t.C }
+func (T realTimer) Stop() bool          { return T.t.Stop() }
+
+// New is used as a constructor for the DelayQueue struct.
+//
+// e.g. myqueue := delayqueue.New()
+//
+func New() *DelayQueue {
+	return NewWithClock(realClock{})
+}
+
+// NewWithClock is used as a constructor for a DelayQueue
+// which tells the time with the given clock.
+//
+// e.g. myqueue := delayqueue.NewWithClock(fakeClock)
+//
+func NewWithClock(clock Clock) *DelayQueue {
+	less := func(a, b interface{}) bool {
+		x, y := a.(*item), b.(*item)
+		if x.readyAt.Equal(y.readyAt) {
+			return x.seq < y.seq
+		}
+		return x.readyAt.Before(y.readyAt)
+	}
+	return &DelayQueue{items: heap.New(less), clock: clock, wake: make(chan struct{})}
+}
+
+// Size returns the number of elements in the queue,
+// whether they are due or not.
+//
+// e.g. (1@10s, 2@20s).Size() => 2
+//
+func (Q *DelayQueue) Size() int {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	return Q.items.Size()
+}
+
+// Len is an alias for Size().
+func (Q *DelayQueue) Len() int {
+	return Q.Size()
+}
+
+// Empty returns true if the queue is empty.
+//
+// e.g. ().Empty() => true
+//
+func (Q *DelayQueue) Empty() bool {
+	return Q.Size() == 0
+}
+
+// Offer places an element in the queue, to be taken
+// once readyAt has passed.
+//
+// e.g. myqueue.Offer(job, time.Now().Add(30*time.Second))
+//
+func (Q *DelayQueue) Offer(V Elem, readyAt time.Time) {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	it := &item{V, readyAt, Q.seq}
+	Q.seq++
+	Q.items.Push(it)
+
+	if Q.items.Peek() == it {
+		close(Q.wake)
+		Q.wake = make(chan struct{})
+	}
+}
+
+// OfferAfter places an element in the queue, to be
+// taken once the duration has passed.
+//
+// e.g. myqueue.OfferAfter(job, 30*time.Second)
+//
+func (Q *DelayQueue) OfferAfter(V Elem, d time.Duration) {
+	Q.Offer(V, Q.clock.Now().Add(d))
+}
+
+// Poll returns the element with the earliest deadline and removes
+// it, and true, if its deadline has passed. Otherwise it returns
+// false without waiting.
+//
+// e.g. (1@past, 2@future).Poll() => 1, true
+//       -----^------ .Poll() => nil, false
+//
+func (Q *DelayQueue) Poll() (Elem, bool) {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	it, _ := Q.due()
+	if it == nil {
+		return nil, false
+	}
+	Q.items.Pop()
+	return it.value, true
+}
+
+// Take waits until the element with the earliest deadline is due,
+// then removes it and returns it. It returns the error of the
+// context if the context is done first.
+//
+// e.g. (1@10s).Take(ctx) => 1, after 10 seconds
+//
+func (Q *DelayQueue) Take(ctx context.Context) (Elem, error) {
+	for {
+		Q.mu.Lock()
+		it, wait := Q.due()
+		if it != nil {
+			Q.items.Pop()
+			Q.mu.Unlock()
+			return it.value, nil
+		}
+		wake := Q.wake
+		Q.mu.Unlock()
+
+		/* A nil channel blocks forever, while the queue is empty */
+		var timer Timer
+		var fire <-chan time.Time
+		if wait > 0 {
+			timer = Q.clock.NewTimer(wait)
+			fire = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, ctx.Err()
+		case <-wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+		}
+	}
+}
+
+// due returns the item with the earliest deadline if it has passed.
+// Otherwise it returns how long until it does, or 0 if the queue is
+// empty.
+func (Q *DelayQueue) due() (*item, time.Duration) {
+	if Q.items.Empty() {
+		return nil, 0
+	}
+
+	it := Q.items.Peek().(*item)
+	wait := it.readyAt.Sub(Q.clock.Now())
+	if wait <= 0 {
+		return it, 0
+	}
+	return nil, wait
+}
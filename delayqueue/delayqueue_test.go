@@ -0,0 +1,275 @@
+package delayqueue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock which only moves when told to.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+
+	/* Receives once for every call to NewTimer */
+	registered chan struct{}
+}
+
+// A timer waiting for the clock to reach a time.
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// fakeTimer is a Timer of the fakeClock.
+type fakeTimer struct {
+	clock *fakeClock
+	w     *waiter
+}
+
+func (T fakeTimer) C() <-chan time.Time {
+	return T.w.ch
+}
+
+// Stop drops the waiter, so only live timers are pending.
+func (T fakeTimer) Stop() bool {
+	C := T.clock
+	C.mu.Lock()
+	defer C.mu.Unlock()
+
+	for i, w := range C.waiters {
+		if w == T.w {
+			C.waiters = append(C.waiters[:i], C.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		registered: make(chan struct{}, 1024),
+	}
+}
+
+func (C *fakeClock) Now() time.Time {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+	return C.now
+}
+
+func (C *fakeClock) NewTimer(d time.Duration) Timer {
+	C.mu.Lock()
+	w := &waiter{C.now.Add(d), make(chan time.Time, 1)}
+	C.waiters = append(C.waiters, w)
+	C.mu.Unlock()
+
+	C.registered <- struct{}{}
+	return fakeTimer{C, w}
+}
+
+// Advance moves the clock forward and fires the waiters that are due.
+// Fired and stopped waiters are dropped, so only pending ones are left.
+func (C *fakeClock) Advance(d time.Duration) {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+
+	C.now = C.now.Add(d)
+	left := C.waiters[:0]
+	for _, w := range C.waiters {
+		if w.at.After(C.now) {
+			left = append(left, w)
+			continue
+		}
+		w.ch <- C.now
+	}
+	C.waiters = left
+}
+
+// pending returns the number of timers which have neither fired
+// nor been stopped.
+func (C *fakeClock) pending() int {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+	return len(C.waiters)
+}
+
+// waitForTimer waits until NewTimer has been called once more.
+func (C *fakeClock) waitForTimer() {
+	<-C.registered
+}
+
+func TestNew(t *testing.T) {
+	queue := New()
+
+	if queue.Size() != 0 || !queue.Empty() {
+		t.Errorf("New constructor is broken.")
+	}
+	if _, ok := queue.Poll(); ok {
+		t.Errorf("Poll should fail on an empty queue.")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	clock := newFakeClock()
+	queue := NewWithClock(clock)
+
+	queue.OfferAfter("c", 30*time.Second)
+	queue.OfferAfter("a", 10*time.Second)
+	queue.OfferAfter("b", 20*time.Second)
+	queue.OfferAfter("a2", 10*time.Second)
+
+	if _, ok := queue.Poll(); ok {
+		t.Errorf("Poll should not return an element before its deadline.")
+	}
+
+	clock.Advance(20 * time.Second)
+	res := []Elem{}
+	for {
+		x, ok := queue.Poll()
+		if !ok {
+			break
+		}
+		res = append(res, x)
+	}
+	if len(res) != 3 || res[0] != "a" || res[1] != "a2" || res[2] != "b" {
+		t.Errorf("Poll should return the due elements in order, got %v.", res)
+	}
+	if queue.Size() != 1 {
+		t.Errorf("Poll should leave the elements which are not due.")
+	}
+
+	queue.Offer("past", clock.Now().Add(-time.Hour))
+	if x, _ := queue.Poll(); x != "past" {
+		t.Errorf("An element with a passed deadline should be due at once.")
+	}
+}
+
+func TestTake(t *testing.T) {
+	clock := newFakeClock()
+	queue := NewWithClock(clock)
+	queue.OfferAfter(1, 10*time.Second)
+
+	res := make(chan Elem)
+	go func() {
+		x, _ := queue.Take(context.Background())
+		res <- x
+	}()
+
+	clock.waitForTimer()
+	clock.Advance(5 * time.Second)
+	if queue.Size() != 1 || clock.pending() != 1 {
+		t.Fatalf("Take should wait for the deadline.")
+	}
+
+	clock.Advance(5 * time.Second)
+	if x := <-res; x != 1 {
+		t.Errorf("Take should return the element once it is due, got %v.", x)
+	}
+}
+
+func TestTakeEarlierOffer(t *testing.T) {
+	clock := newFakeClock()
+	queue := NewWithClock(clock)
+	queue.OfferAfter("late", time.Hour)
+
+	res := make(chan Elem)
+	go func() {
+		x, _ := queue.Take(context.Background())
+		res <- x
+	}()
+
+	/* Take waits on the late element, then wakes up for the early one */
+	clock.waitForTimer()
+	queue.OfferAfter("early", time.Second)
+	clock.waitForTimer()
+	if clock.pending() != 1 {
+		t.Errorf("Take should stop the timer it no longer waits for.")
+	}
+	clock.Advance(time.Second)
+
+	if x := <-res; x != "early" {
+		t.Errorf("Take should wake up for an earlier element, got %v.", x)
+	}
+}
+
+func TestTakeContext(t *testing.T) {
+	clock := newFakeClock()
+	queue := NewWithClock(clock)
+	queue.OfferAfter(1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+
+	if x, err := queue.Take(ctx); x != nil || err != context.Canceled {
+		t.Errorf("Take should return the error of the context, got %v.", err)
+	}
+	if queue.Size() != 1 || clock.pending() != 0 {
+		t.Errorf("A canceled Take should leave the element and stop its timer.")
+	}
+}
+
+func TestTakeConcurrent(t *testing.T) {
+	clock := newFakeClock()
+	queue := NewWithClock(clock)
+
+	for i := 0; i < 100; i++ {
+		queue.OfferAfter(i, time.Duration(i%10)*time.Second)
+	}
+
+	var wg sync.WaitGroup
+	res := make(chan Elem, 100)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				x, _ := queue.Take(context.Background())
+				res <- x
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	/* Every taker that waits is let go by moving the clock */
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		case <-clock.registered:
+			for clock.pending() > 0 {
+				clock.Advance(time.Second)
+			}
+		}
+	}
+	close(res)
+
+	seen := map[Elem]bool{}
+	for x := range res {
+		seen[x] = true
+	}
+	if len(seen) != 100 || !queue.Empty() {
+		t.Errorf("Take should return every element once, got %d.", len(seen))
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	queue := New()
+	queue.OfferAfter(1, 5*time.Millisecond)
+
+	start := time.Now()
+	if x, err := queue.Take(context.Background()); x != 1 || err != nil {
+		t.Errorf("Take should return the element.")
+	}
+	if time.Since(start) < 5*time.Millisecond {
+		t.Errorf("Take should wait for the deadline.")
+	}
+}
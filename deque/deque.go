@@ -0,0 +1,175 @@
+// Package deque provides a Chase-Lev work-stealing deque. Its owner
+// pushes and pops at the bottom like a stack, while other goroutines
+// steal from the top, without any lock.
+//
+// It is meant for schedulers where every worker keeps its own tasks
+// and idle workers take tasks from busy ones.
+package deque
+
+import "sync/atomic"
+
+// A deque has the index of its top and its bottom, and a ring of
+// slots which holds the elements from top to bottom. Only the owner
+// moves the bottom and replaces the ring; thieves race for the top
+// with compare-and-swap, and so does the owner for the last element.
+//
+// e.g. PushBottom(1), PushBottom(2), PushBottom(3):
+//      top -> 1, 2, 3 <- bottom
+//      Steal() => 1, PopBottom() => 3
+//
+// Only one goroutine, the owner, may call PushBottom and PopBottom.
+// Any goroutine may call Steal, Size and Empty.
+type Deque struct {
+	top    atomic.Int64
+	bottom atomic.Int64
+	ring   atomic.Pointer[ring]
+}
+
+// A ring is a circular array of slots whose size is a power of two.
+// A full ring is replaced by one twice as big, but thieves may still
+// read from the old one.
+type ring struct {
+	mask  int64
+	slots []atomic.Pointer[Elem]
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// New is used as a constructor for the Deque struct.
+//
+// e.g. mydeque := deque.New()
+//
+func New() *Deque {
+	D := &Deque{}
+	D.ring.Store(newRing(32))
+	return D
+}
+
+// Size returns the number of elements in the deque. With thieves
+// about, it may be out of date by the time it returns.
+//
+// e.g. (1,2,3).Size() => 3
+//
+func (D *Deque) Size() int {
+	size := D.bottom.Load() - D.top.Load()
+	if size < 0 {
+		return 0
+	}
+	return int(size)
+}
+
+// Len is an alias for Size().
+func (D *Deque) Len() int {
+	return D.Size()
+}
+
+// Empty returns true if the deque is empty.
+//
+// e.g. ().Empty() => true
+//
+func (D *Deque) Empty() bool {
+	return D.Size() == 0
+}
+
+// PushBottom places an element at the bottom of the deque.
+// It may only be called by the owner.
+//
+// e.g. (1,2).PushBottom(3) => (1,2,3)
+//
+func (D *Deque) PushBottom(E Elem) {
+	b := D.bottom.Load()
+	t := D.top.Load()
+	r := D.ring.Load()
+
+	if b-t >= int64(len(r.slots))-1 {
+		r = r.grow(t, b)
+		D.ring.Store(r)
+	}
+	r.put(b, E)
+	D.bottom.Store(b + 1)
+}
+
+// PopBottom removes the element at the bottom of the deque and
+// returns it, and true. It returns false if the deque is empty or
+// a thief took the last element. It may only be called by the owner.
+//
+// e.g. (1,2,3).PopBottom() => 3, true
+//
+func (D *Deque) PopBottom() (Elem, bool) {
+	b := D.bottom.Load() - 1
+	r := D.ring.Load()
+	D.bottom.Store(b)
+	t := D.top.Load()
+
+	if t > b {
+		/* The deque was empty */
+		D.bottom.Store(b + 1)
+		return nil, false
+	}
+
+	E := r.get(b)
+	if t == b {
+		/* The last element, which a thief may be after as well */
+		won := D.top.CompareAndSwap(t, t+1)
+		D.bottom.Store(b + 1)
+		if !won {
+			return nil, false
+		}
+	}
+	r.put(b, nil)
+	return E, true
+}
+
+// Steal removes the element at the top of the deque and returns it,
+// and true. It returns false if the deque is empty or another thief,
+// or the owner, got there first, in which case it may be tried again.
+//
+// e.g. (1,2,3).Steal() => 1, true
+//
+func (D *Deque) Steal() (Elem, bool) {
+	t := D.top.Load()
+	b := D.bottom.Load()
+	if t >= b {
+		return nil, false
+	}
+
+	E := D.ring.Load().get(t)
+	if !D.top.CompareAndSwap(t, t+1) {
+		return nil, false
+	}
+	return E, true
+}
+
+// newRing returns an empty ring with the given size,
+// which must be a power of two.
+func newRing(size int64) *ring {
+	return &ring{size - 1, make([]atomic.Pointer[Elem], size)}
+}
+
+// get returns the element at index i.
+func (R *ring) get(i int64) Elem {
+	if p := R.slots[i&R.mask].Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// put stores an element at index i. A nil element
+// empties the slot.
+func (R *ring) put(i int64, E Elem) {
+	if E == nil {
+		R.slots[i&R.mask].Store(nil)
+		return
+	}
+	R.slots[i&R.mask].Store(&E)
+}
+
+// grow returns a ring twice as big holding the elements from t to b.
+func (R *ring) grow(t, b int64) *ring {
+	res := newRing(2 * int64(len(R.slots)))
+	for i := t; i < b; i++ {
+		res.slots[i&res.mask].Store(R.slots[i&R.mask].Load())
+	}
+	return res
+}
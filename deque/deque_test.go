@@ -0,0 +1,122 @@
+package deque
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	deque := New()
+
+	if deque.Size() != 0 || !deque.Empty() {
+		t.Errorf("New constructor is broken.")
+	}
+	if _, ok := deque.PopBottom(); ok {
+		t.Errorf("PopBottom should fail on an empty deque.")
+	}
+	if _, ok := deque.Steal(); ok {
+		t.Errorf("Steal should fail on an empty deque.")
+	}
+}
+
+func TestPushPop(t *testing.T) {
+	deque := New()
+	for i := 0; i < 1000; i++ {
+		deque.PushBottom(i)
+	}
+	if deque.Size() != 1000 {
+		t.Errorf("PushBottom should add elements.")
+	}
+
+	for i := 999; i >= 0; i-- {
+		if x, ok := deque.PopBottom(); !ok || x != i {
+			t.Fatalf("PopBottom should return the last element, got %v want %d.", x, i)
+		}
+	}
+	if _, ok := deque.PopBottom(); ok || !deque.Empty() {
+		t.Errorf("PopBottom should remove elements.")
+	}
+}
+
+func TestSteal(t *testing.T) {
+	deque := New()
+	for i := 0; i < 100; i++ {
+		deque.PushBottom(i)
+	}
+
+	for i := 0; i < 50; i++ {
+		if x, ok := deque.Steal(); !ok || x != i {
+			t.Fatalf("Steal should return the first element, got %v want %d.", x, i)
+		}
+	}
+	/* Push past the end of the ring after some were stolen */
+	for i := 100; i < 200; i++ {
+		deque.PushBottom(i)
+	}
+	if x, _ := deque.PopBottom(); x != 199 {
+		t.Errorf("PopBottom should return the last element, got %v.", x)
+	}
+	if x, _ := deque.Steal(); x != 50 {
+		t.Errorf("Steal should return the first element, got %v.", x)
+	}
+	if deque.Size() != 148 {
+		t.Errorf("Size should count what is left, got %d.", deque.Size())
+	}
+}
+
+func TestNil(t *testing.T) {
+	deque := New()
+	deque.PushBottom(nil)
+
+	if x, ok := deque.PopBottom(); !ok || x != nil {
+		t.Errorf("A nil element should be popped like any other.")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	const n = 100000
+	deque := New()
+	seen := make([]int32, n)
+
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() || !deque.Empty() {
+				if x, ok := deque.Steal(); ok {
+					atomic.AddInt32(&seen[x.(int)], 1)
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		deque.PushBottom(i)
+		if i%3 == 0 {
+			if x, ok := deque.PopBottom(); ok {
+				atomic.AddInt32(&seen[x.(int)], 1)
+			}
+		}
+	}
+	for {
+		x, ok := deque.PopBottom()
+		if !ok {
+			break
+		}
+		atomic.AddInt32(&seen[x.(int)], 1)
+	}
+	done.Store(true)
+	wg.Wait()
+
+	for i, c := range seen {
+		if c != 1 {
+			t.Fatalf("Element %d was taken %d times.", i, c)
+		}
+	}
+}
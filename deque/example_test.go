@@ -0,0 +1,66 @@
+package deque_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/emnl/goods/deque"
+)
+
+// A task sums the numbers from lo to hi. A big task splits in two
+// and pushes the halves, which idle workers may steal.
+type task struct{ lo, hi int }
+
+// This example fans a recursive sum out over four workers. Every
+// worker owns a deque, runs its own tasks from the bottom, and when
+// it runs out it steals from the top of the others' deques, where
+// the biggest tasks are.
+func Example() {
+	const workers = 4
+	deques := make([]*deque.Deque, workers)
+	for i := range deques {
+		deques[i] = deque.New()
+	}
+	deques[0].PushBottom(task{1, 1000000})
+
+	var sum, pending int64 = 0, 1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for atomic.LoadInt64(&pending) > 0 {
+				x, ok := deques[w].PopBottom()
+				for v := 1; !ok && v < workers; v++ {
+					x, ok = deques[(w+v)%workers].Steal()
+				}
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+
+				t := x.(task)
+				if t.hi-t.lo < 1000 {
+					s := int64(0)
+					for i := t.lo; i <= t.hi; i++ {
+						s += int64(i)
+					}
+					atomic.AddInt64(&sum, s)
+					atomic.AddInt64(&pending, -1)
+					continue
+				}
+
+				mid := (t.lo + t.hi) / 2
+				atomic.AddInt64(&pending, 1)
+				deques[w].PushBottom(task{t.lo, mid})
+				deques[w].PushBottom(task{mid + 1, t.hi})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	fmt.Println(sum)
+	// Output: 500000500000
+}
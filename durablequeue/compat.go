@@ -0,0 +1,120 @@
+package durablequeue
+
+import (
+	"sync"
+
+	"github.com/emnl/goods/queue"
+)
+
+// Compat wraps a Queue in the API of queue.Queue, so it can take the
+// place of one: Offer and Poll don't return errors. The first error
+// is kept instead, and Err returns it. The elements are queue.Elem,
+// so a Compat satisfies the interfaces a queue.Queue does.
+//
+// Once there is an error, the Compat acts as an empty queue: Offer
+// does nothing, Poll returns nil, and Size and Empty say so, so that
+// a loop draining it ends. The elements are still in the Queue.
+//
+// e.g. jobs := myqueue.Compat()
+//      jobs.Offer(job)
+//      if err := jobs.Err(); err != nil { ... }
+//
+type Compat struct {
+	*Queue
+	mu  sync.Mutex
+	err error
+}
+
+// Compat returns the queue wrapped in the API of queue.Queue.
+func (Q *Queue) Compat() *Compat {
+	return &Compat{Queue: Q}
+}
+
+// Offer places an element last in the queue.
+//
+// e.g. (1,2,3).Offer(4) => (1,2,3,4)
+//
+func (C *Compat) Offer(V queue.Elem) {
+	if C.Err() != nil {
+		return
+	}
+	C.keep(C.Queue.Offer(V))
+}
+
+// Poll returns the first element in the queue
+// and removes it.
+//
+// e.g. (1,2,3).Poll() => 1
+//       --^-- .Poll() => 2
+//
+func (C *Compat) Poll() queue.Elem {
+	if C.Err() != nil {
+		return nil
+	}
+	V, err := C.Queue.Poll()
+	C.keep(err)
+	return V
+}
+
+// Enqueue is an alias for Offer().
+func (C *Compat) Enqueue(V queue.Elem) { C.Offer(V) }
+
+// Dequeue is an alias for Poll().
+func (C *Compat) Dequeue() queue.Elem { return C.Poll() }
+
+// Err returns the first error of Offer or Poll, or nil.
+func (C *Compat) Err() error {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+
+	return C.err
+}
+
+// keep keeps the error if it is the first.
+func (C *Compat) keep(err error) {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+
+	if C.err == nil {
+		C.err = err
+	}
+}
+
+// Peek returns the first element in the queue
+// without removing it, or nil once there is an error.
+//
+// e.g. (1,2,3).Peek() => 1
+//       --^-- .Peek() => 1
+//
+func (C *Compat) Peek() queue.Elem {
+	if C.Err() != nil {
+		return nil
+	}
+	return C.Queue.Peek()
+}
+
+// Size returns the number of elements in the queue,
+// or 0 once there is an error.
+//
+// e.g. (1,2,3).Size() => 3
+//
+func (C *Compat) Size() int {
+	if C.Err() != nil {
+		return 0
+	}
+	return C.Queue.Size()
+}
+
+// Len is an alias for Size().
+func (C *Compat) Len() int {
+	return C.Size()
+}
+
+// Empty returns true if the queue is empty,
+// or once there is an error.
+//
+// e.g. ().Empty() => true
+//
+func (C *Compat) Empty() bool {
+	return C.Size() == 0
+}
@@ -0,0 +1,48 @@
+package durablequeue
+
+import (
+	"testing"
+
+	"github.com/emnl/goods/queue"
+)
+
+// fifo is the part of queue.Queue a Compat stands in for.
+type fifo interface {
+	Offer(V queue.Elem)
+	Poll() queue.Elem
+	Peek() queue.Elem
+	Size() int
+	Empty() bool
+}
+
+var (
+	_ fifo = queue.New()
+	_ fifo = &Compat{}
+)
+
+func TestCompat(t *testing.T) {
+	q := open(t, t.TempDir(), Options{})
+	c := q.Compat()
+
+	c.Offer(1)
+	c.Enqueue(2)
+	if c.Size() != 2 || c.Peek() != 1 || c.Poll() != 1 || c.Dequeue() != 2 || c.Poll() != nil {
+		t.Errorf("Compat should behave like a queue.Queue.")
+	}
+	if c.Err() != nil {
+		t.Errorf("Err should be nil without errors, got %v.", c.Err())
+	}
+
+	c.Offer(3)
+	q.Close()
+	c.Offer(4)
+	if c.Err() != ErrClosed {
+		t.Errorf("Err should return the first error, got %v.", c.Err())
+	}
+	if c.Poll() != nil || q.Size() != 1 {
+		t.Errorf("Compat should stop changing the queue after an error.")
+	}
+	if !c.Empty() || c.Size() != 0 || c.Peek() != nil {
+		t.Errorf("Compat should look empty after an error, so a drain loop ends.")
+	}
+}
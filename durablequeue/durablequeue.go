@@ -0,0 +1,407 @@
+// Package durablequeue provides a first-in-first-out queue which
+// survives a crash. Every Offer and Poll is appended to a log on disk
+// before it takes effect, and the log is replayed when the queue is
+// opened again.
+//
+// Offer and Poll return the errors of the disk, so their signatures
+// differ from the ones of queue.Queue. Compat wraps a queue in the
+// exact API of queue.Queue, keeping the first error for Err instead.
+package durablequeue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrClosed is returned when a closed queue is used.
+var ErrClosed = errors.New("Queue is closed.")
+
+// SyncPolicy decides how often the log is flushed to disk.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes the log after every Offer and Poll. Nothing
+	// is lost in a crash, but every change waits for the disk.
+	SyncAlways SyncPolicy = iota
+
+	// SyncBatch flushes the log after every Options.SyncEvery changes.
+	// At most that many changes are lost in a crash.
+	SyncBatch
+
+	// SyncNever leaves the flushing to the operating system, or to
+	// Sync and Close. Whatever it hasn't written is lost in a crash.
+	SyncNever
+)
+
+// Options are used to tune a queue. The zero value is safe to use.
+type Options struct {
+	// SegmentSize is the size in bytes after which a new segment
+	// file is started. It defaults to 4 MiB.
+	SegmentSize int64
+
+	// Sync decides how often the log is flushed to disk.
+	Sync SyncPolicy
+
+	// SyncEvery is the number of changes between flushes with
+	// SyncBatch. It defaults to 100.
+	SyncEvery int
+}
+
+// A queue keeps its elements in memory along with their sequence
+// numbers, and logs every change to a directory of segment files.
+// The last segment is the one being written to; the ones before it
+// are removed once every element they offered has been polled.
+//
+// e.g. Offer(1), Offer(2), Poll(), Offer(3):
+//      00000000000000000000.seg: offer 0:1, offer 1:2, poll 0
+//      00000000000000000001.seg: offer 2:3
+//
+// The queue is thread-safe.
+type Queue struct {
+	mu       sync.Mutex
+	dir      string
+	opts     Options
+	elems    []entry
+	next     uint64
+	segments []segment
+	file     *os.File
+	size     int64
+	unsynced int
+	closed   bool
+}
+
+// An element of the queue and its sequence number.
+type entry struct {
+	seq  uint64
+	elem Elem
+}
+
+// Elem is used as a generic for any type of value. The elements are
+// stored with encoding/gob, so types other than the basic ones have to
+// be registered with gob.Register.
+type Elem interface{}
+
+// Open is used as a constructor for the Queue struct. It opens the
+// queue stored in dir, or creates an empty one. A segment cut short
+// by a crash in the middle of a write is truncated to the last whole
+// record; any other damage to the log returns an error.
+//
+// e.g. myqueue, err := durablequeue.Open("/var/lib/jobs", durablequeue.Options{})
+//
+func Open(dir string, opts Options) (*Queue, error) {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = 4 << 20
+	}
+	if opts.SyncEvery <= 0 {
+		opts.SyncEvery = 100
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ids, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		ids = []uint64{0}
+	}
+
+	Q := &Queue{dir: dir, opts: opts}
+	for i, id := range ids {
+		if err := Q.replay(id, i == len(ids)-1); err != nil {
+			return nil, err
+		}
+	}
+
+	active := ids[len(ids)-1]
+	Q.file, err = os.OpenFile(segmentPath(dir, active), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := Q.compact(); err != nil {
+		Q.file.Close()
+		return nil, err
+	}
+	return Q, nil
+}
+
+// Offer places an element last in the queue. The element is in the
+// log when Offer returns, and on disk as the SyncPolicy says.
+//
+// e.g. (1,2,3).Offer(4) => (1,2,3,4)
+//
+func (Q *Queue) Offer(V Elem) error {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	if Q.closed {
+		return ErrClosed
+	}
+
+	buf, err := encodeRecord(recOffer, Q.next, V)
+	if err != nil {
+		return err
+	}
+	if err := Q.write(buf); err != nil {
+		return err
+	}
+
+	seg := &Q.segments[len(Q.segments)-1]
+	seg.offers++
+	seg.last = Q.next
+	Q.elems = append(Q.elems, entry{Q.next, V})
+	Q.next++
+	return nil
+}
+
+// Poll returns the first element in the queue and removes it.
+// It returns nil if the queue is empty. An error means the poll
+// didn't happen; a segment which can't be removed afterwards is
+// left for the next Poll, and Sync reports it.
+//
+// e.g. (1,2,3).Poll() => 1
+//       --^-- .Poll() => 2
+//
+func (Q *Queue) Poll() (Elem, error) {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	if Q.closed {
+		return nil, ErrClosed
+	}
+	if len(Q.elems) == 0 {
+		return nil, nil
+	}
+
+	first := Q.elems[0]
+	buf, _ := encodeRecord(recPoll, first.seq, nil)
+	if err := Q.write(buf); err != nil {
+		return nil, err
+	}
+
+	Q.elems[0] = entry{}
+	Q.elems = Q.elems[1:]
+
+	/* The poll is logged, so a failed compaction is retried later */
+	Q.compact()
+	return first.elem, nil
+}
+
+// Peek returns the first element in the queue
+// without removing it.
+//
+// e.g. (1,2,3).Peek() => 1
+//       --^-- .Peek() => 1
+//
+func (Q *Queue) Peek() Elem {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	if len(Q.elems) == 0 {
+		return nil
+	}
+	return Q.elems[0].elem
+}
+
+// Enqueue is an alias for Offer().
+func (Q *Queue) Enqueue(V Elem) error { return Q.Offer(V) }
+
+// Dequeue is an alias for Poll().
+func (Q *Queue) Dequeue() (Elem, error) { return Q.Poll() }
+
+// Size returns the number of elements in the queue.
+//
+// e.g. (1,2,3).Size() => 3
+//
+func (Q *Queue) Size() int {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	return len(Q.elems)
+}
+
+// Len is an alias for Size().
+func (Q *Queue) Len() int {
+	return Q.Size()
+}
+
+// Empty returns true if the queue is empty.
+//
+// e.g. ().Empty() => true
+//
+func (Q *Queue) Empty() bool {
+	return Q.Size() == 0
+}
+
+// Sync flushes the log to disk, whatever the SyncPolicy, and removes
+// the segments which are no longer needed. It returns the error of a
+// segment which can't be removed.
+func (Q *Queue) Sync() error {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	if Q.closed {
+		return ErrClosed
+	}
+	if err := Q.sync(); err != nil {
+		return err
+	}
+	return Q.compact()
+}
+
+// Close flushes the log to disk and closes the queue. The queue
+// can be opened again with Open.
+func (Q *Queue) Close() error {
+	Q.mu.Lock()
+	defer Q.mu.Unlock()
+
+	if Q.closed {
+		return ErrClosed
+	}
+	Q.closed = true
+
+	err := Q.file.Sync()
+	if cerr := Q.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// replay reads a segment and applies its records. A torn tail is
+// cut off the last segment, which is the only one being written to,
+// but only if no whole record follows the damage.
+func (Q *Queue) replay(id uint64, last bool) error {
+	path := segmentPath(Q.dir, id)
+	data, err := os.ReadFile(path)
+	if err != nil && !(last && os.IsNotExist(err)) {
+		return err
+	}
+
+	records, off, err := readRecords(data)
+	if err != nil {
+		torn := err == errTorn || err == errChecksum
+		if !last || !torn || validAfter(data, off) {
+			return fmt.Errorf("Segment %d is corrupt at offset %d: %v", id, off, err)
+		}
+		if err := os.Truncate(path, int64(off)); err != nil {
+			return err
+		}
+	}
+
+	Q.segments = append(Q.segments, segment{id: id})
+	seg := &Q.segments[len(Q.segments)-1]
+	for _, r := range records {
+		if r.seq >= Q.next {
+			Q.next = r.seq + 1
+		}
+
+		if r.typ == recOffer {
+			if len(Q.elems) > 0 && r.seq <= Q.elems[len(Q.elems)-1].seq {
+				return fmt.Errorf("Segment %d offers element %d out of order.", id, r.seq)
+			}
+			Q.elems = append(Q.elems, entry{r.seq, r.elem})
+			seg.offers++
+			seg.last = r.seq
+			continue
+		}
+
+		/* A poll of an element which is not here was compacted away */
+		if len(Q.elems) == 0 || r.seq < Q.elems[0].seq {
+			continue
+		}
+		if r.seq > Q.elems[0].seq {
+			return fmt.Errorf("Segment %d polls element %d out of order.", id, r.seq)
+		}
+		Q.elems[0] = entry{}
+		Q.elems = Q.elems[1:]
+	}
+	Q.size = int64(off)
+	return nil
+}
+
+// write appends a record to the log, starting a new segment first if
+// the current one is full. A failed write is cut off again, so no
+// half record is left in the middle of the log.
+func (Q *Queue) write(buf []byte) error {
+	if Q.size >= Q.opts.SegmentSize {
+		if err := Q.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := Q.file.Write(buf); err != nil {
+		Q.file.Truncate(Q.size)
+		return err
+	}
+	Q.size += int64(len(buf))
+	Q.unsynced++
+
+	switch {
+	case Q.opts.Sync == SyncAlways,
+		Q.opts.Sync == SyncBatch && Q.unsynced >= Q.opts.SyncEvery:
+		return Q.sync()
+	}
+	return nil
+}
+
+// rotate closes the current segment and starts a new one.
+func (Q *Queue) rotate() error {
+	if err := Q.sync(); err != nil {
+		return err
+	}
+
+	id := Q.segments[len(Q.segments)-1].id + 1
+	file, err := os.OpenFile(segmentPath(Q.dir, id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if Q.opts.Sync != SyncNever {
+		if err := syncDir(Q.dir); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	Q.file.Close()
+	Q.file, Q.size = file, 0
+	Q.segments = append(Q.segments, segment{id: id})
+	return nil
+}
+
+// compact removes the segments before the current one whose
+// offers have all been polled.
+func (Q *Queue) compact() error {
+	head := Q.next
+	if len(Q.elems) > 0 {
+		head = Q.elems[0].seq
+	}
+
+	for len(Q.segments) > 1 && Q.segments[0].polled(head) {
+		err := os.Remove(segmentPath(Q.dir, Q.segments[0].id))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		Q.segments = Q.segments[1:]
+	}
+	return nil
+}
+
+// sync flushes the current segment to disk.
+func (Q *Queue) sync() error {
+	Q.unsynced = 0
+	return Q.file.Sync()
+}
+
+// syncDir flushes a directory to disk, so the files
+// created in it survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
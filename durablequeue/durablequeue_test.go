@@ -0,0 +1,313 @@
+package durablequeue
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type job struct {
+	Name  string
+	Tries int
+}
+
+func init() {
+	gob.Register(job{})
+}
+
+// open opens the queue in dir and fails the test on an error.
+func open(t *testing.T, dir string, opts Options) *Queue {
+	q, err := Open(dir, opts)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return q
+}
+
+// pollAll polls every element of the queue.
+func pollAll(t *testing.T, q *Queue) []Elem {
+	res := []Elem{}
+	for !q.Empty() {
+		x, err := q.Poll()
+		if err != nil {
+			t.Fatalf("Poll failed: %v", err)
+		}
+		res = append(res, x)
+	}
+	return res
+}
+
+// segments returns the number of segment files in dir.
+func segments(t *testing.T, dir string) int {
+	ids, err := listSegments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(ids)
+}
+
+func TestOfferPoll(t *testing.T) {
+	q := open(t, t.TempDir(), Options{})
+	defer q.Close()
+
+	if x, err := q.Poll(); x != nil || err != nil || q.Peek() != nil || !q.Empty() {
+		t.Errorf("A new queue should be empty.")
+	}
+
+	q.Offer(1)
+	q.Offer("two")
+	q.Offer(job{"three", 3})
+
+	if q.Size() != 3 || q.Peek() != 1 {
+		t.Errorf("Offer should add elements last.")
+	}
+	res := pollAll(t, q)
+	if len(res) != 3 || res[0] != 1 || res[1] != "two" || res[2] != (job{"three", 3}) {
+		t.Errorf("Poll should return the elements in order, got %v.", res)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{SegmentSize: 64})
+	for i := 0; i < 100; i++ {
+		q.Offer(i)
+	}
+	for i := 0; i < 40; i++ {
+		q.Poll()
+	}
+	q.Close()
+
+	if q.Offer(1) != ErrClosed || q.Close() != ErrClosed {
+		t.Errorf("A closed queue should return ErrClosed.")
+	}
+
+	q = open(t, dir, Options{SegmentSize: 64})
+	if q.Size() != 60 || q.Peek() != 40 {
+		t.Fatalf("Open should replay the log, got size %d.", q.Size())
+	}
+	q.Offer(100)
+	q.Close()
+
+	q = open(t, dir, Options{SegmentSize: 64})
+	defer q.Close()
+	res := pollAll(t, q)
+	for i, x := range res {
+		if x != 40+i {
+			t.Fatalf("Open should keep the order of the elements, got %v.", res)
+		}
+	}
+	if len(res) != 61 {
+		t.Errorf("Open should keep every element, got %d.", len(res))
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{SegmentSize: 128, Sync: SyncNever})
+	for i := 0; i < 200; i++ {
+		q.Offer(i)
+	}
+	if segments(t, dir) < 10 {
+		t.Fatalf("Offer should start new segments, got %d.", segments(t, dir))
+	}
+
+	for i := 0; i < 150; i++ {
+		q.Poll()
+	}
+	before := segments(t, dir)
+	pollAll(t, q)
+	q.Offer(200)
+	q.Poll()
+	if n := segments(t, dir); n >= before || n > 2 {
+		t.Errorf("Poll should remove the segments that were consumed, %d left.", n)
+	}
+	q.Close()
+
+	q = open(t, dir, Options{SegmentSize: 128})
+	defer q.Close()
+	if !q.Empty() {
+		t.Errorf("A compacted log should replay to the same queue.")
+	}
+	q.Offer(201)
+	if x, _ := q.Poll(); x != 201 {
+		t.Errorf("A compacted log should still take offers, got %v.", x)
+	}
+}
+
+func TestCompactionFailure(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{SegmentSize: 128, Sync: SyncNever})
+	defer q.Close()
+	for i := 0; i < 50; i++ {
+		q.Offer(i)
+	}
+
+	/* A non-empty directory can't be removed like a segment */
+	first := segmentPath(dir, q.segments[0].id)
+	os.Remove(first)
+	os.Mkdir(first, 0755)
+	os.WriteFile(filepath.Join(first, "x"), nil, 0644)
+
+	if res := pollAll(t, q); len(res) != 50 {
+		t.Errorf("Poll should not fail when a segment can't be removed.")
+	}
+	if q.Sync() == nil {
+		t.Errorf("Sync should report a segment which can't be removed.")
+	}
+
+	os.RemoveAll(first)
+	if err := q.Sync(); err != nil || len(q.segments) != 1 {
+		t.Errorf("Sync should remove the segment once it can, got %v.", err)
+	}
+}
+
+func TestTornTail(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{})
+	for i := 0; i < 5; i++ {
+		q.Offer(i)
+	}
+	q.Poll()
+	q.Close()
+
+	path := segmentPath(dir, 0)
+	data, _ := os.ReadFile(path)
+	for cut := 1; cut <= headerSize; cut++ {
+		os.WriteFile(path, data[:len(data)-cut], 0644)
+
+		q = open(t, dir, Options{})
+		/* The poll record is torn, so 0 is back */
+		if q.Size() != 5 || q.Peek() != 0 {
+			t.Fatalf("Open should drop the torn record, got size %d.", q.Size())
+		}
+		q.Close()
+
+		if info, _ := os.Stat(path); info.Size() != int64(len(data)-headerSize) {
+			t.Fatalf("Open should truncate the torn record.")
+		}
+	}
+
+	/* A torn record which fails its checksum */
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(path, data, 0644)
+
+	q = open(t, dir, Options{})
+	defer q.Close()
+	q.Poll()
+	q.Offer(5)
+	q.Close()
+
+	q = open(t, dir, Options{})
+	res := pollAll(t, q)
+	if len(res) != 5 || res[0] != 1 || res[4] != 5 {
+		t.Errorf("A truncated log should take new records, got %v.", res)
+	}
+}
+
+func TestCorruption(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{SegmentSize: 64})
+	for i := 0; i < 20; i++ {
+		q.Offer(i)
+	}
+	q.Close()
+
+	if segments(t, dir) < 2 {
+		t.Fatalf("The test needs more than one segment.")
+	}
+
+	/* Flip a byte of a payload in the middle of the first segment */
+	path := segmentPath(dir, 0)
+	data, _ := os.ReadFile(path)
+	data[headerSize+2] ^= 0xff
+	os.WriteFile(path, data, 0644)
+
+	if _, err := Open(dir, Options{SegmentSize: 64}); err == nil {
+		t.Errorf("Open should refuse a corrupt segment which is not the last.")
+	}
+}
+
+func TestCorruptionLastSegment(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{})
+	for i := 0; i < 5; i++ {
+		q.Offer(i)
+	}
+	q.Close()
+
+	/* Flip a byte of the first record, which has whole records after it */
+	path := segmentPath(dir, 0)
+	data, _ := os.ReadFile(path)
+	data[headerSize+2] ^= 0xff
+	os.WriteFile(path, data, 0644)
+
+	if _, err := Open(dir, Options{}); err == nil {
+		t.Errorf("Open should refuse a damaged record which is not the tail.")
+	}
+	if info, _ := os.Stat(path); info.Size() != int64(len(data)) {
+		t.Errorf("Open should not truncate records after the damage.")
+	}
+}
+
+func TestUnknownFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(dir, "x.seg"), []byte("hello"), 0644)
+
+	q := open(t, dir, Options{})
+	defer q.Close()
+	if !q.Empty() {
+		t.Errorf("Open should ignore files which are not segments.")
+	}
+}
+
+func TestSyncPolicies(t *testing.T) {
+	for _, opts := range []Options{{Sync: SyncAlways}, {Sync: SyncBatch, SyncEvery: 3}, {Sync: SyncNever}} {
+		dir := t.TempDir()
+		q := open(t, dir, opts)
+		for i := 0; i < 10; i++ {
+			q.Offer(i)
+		}
+		if q.Sync() != nil {
+			t.Errorf("Sync should flush the log.")
+		}
+		q.Close()
+
+		q = open(t, dir, opts)
+		if q.Size() != 10 {
+			t.Errorf("Every policy should keep the elements on Close.")
+		}
+		q.Close()
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, Options{SegmentSize: 512, Sync: SyncNever})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.Offer(g*100 + i)
+				if i%2 == 0 {
+					q.Poll()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	size := q.Size()
+	q.Close()
+	q = open(t, dir, Options{})
+	defer q.Close()
+	if q.Size() != size || size != 200 {
+		t.Errorf("Open should replay concurrent changes, got %d want %d.", q.Size(), size)
+	}
+}
@@ -0,0 +1,166 @@
+package durablequeue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Every record starts with the length of its payload and a CRC-32 of
+// the rest of the record, followed by its type, the sequence number of
+// the element, and the payload. The lengths and numbers are big endian.
+//
+// e.g. [len uint32][crc uint32][type byte][seq uint64][payload]
+//
+// An offer record holds the gob encoded element as payload. A poll
+// record has no payload; its sequence number is the element it polled.
+const (
+	headerSize = 4 + 4 + 1 + 8
+	maxPayload = 1 << 30
+
+	recOffer = 1
+	recPoll  = 2
+
+	segExt = ".seg"
+)
+
+// Errors which mark the torn tail of a segment, left behind when the
+// process stopped in the middle of a write.
+var (
+	errTorn     = errors.New("Segment ends inside a record.")
+	errChecksum = errors.New("Segment record fails its checksum.")
+)
+
+// A record as read back from a segment.
+type record struct {
+	typ  byte
+	seq  uint64
+	elem Elem
+}
+
+// A segment is a file of records, named after its id. It counts the
+// offers it holds and remembers the sequence number of the last, so
+// it can be removed once every one of them has been polled.
+type segment struct {
+	id     uint64
+	offers int
+	last   uint64
+}
+
+// polled returns true if every offer in the segment came before head.
+func (S *segment) polled(head uint64) bool {
+	return S.offers == 0 || S.last < head
+}
+
+// segmentPath returns the path of the segment with the given id.
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", id, segExt))
+}
+
+// listSegments returns the ids of the segments in dir, in order.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uint64{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// encodeRecord returns a record ready to be written.
+func encodeRecord(typ byte, seq uint64, V Elem) ([]byte, error) {
+	var payload bytes.Buffer
+	if typ == recOffer {
+		if err := gob.NewEncoder(&payload).Encode(&V); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := make([]byte, headerSize, headerSize+payload.Len())
+	binary.BigEndian.PutUint32(buf[0:], uint32(payload.Len()))
+	buf[8] = typ
+	binary.BigEndian.PutUint64(buf[9:], seq)
+	buf = append(buf, payload.Bytes()...)
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(buf[8:]))
+	return buf, nil
+}
+
+// readRecords decodes the records of a segment. It stops at the first
+// record that is cut short or fails its checksum, or can't be decoded,
+// and returns the records before it, the offset where it starts, and
+// an error. The error is errTorn or errChecksum if the rest of the
+// segment looks like a torn write.
+func readRecords(data []byte) ([]record, int, error) {
+	res := []record{}
+	off := 0
+	for off < len(data) {
+		if len(data)-off < headerSize {
+			return res, off, errTorn
+		}
+
+		n := binary.BigEndian.Uint32(data[off:])
+		if n > maxPayload || uint64(len(data)-off-headerSize) < uint64(n) {
+			return res, off, errTorn
+		}
+		body := data[off+8 : off+headerSize+int(n)]
+		if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[off+4:]) {
+			return res, off, errChecksum
+		}
+
+		r := record{typ: body[0], seq: binary.BigEndian.Uint64(body[1:])}
+		switch r.typ {
+		case recOffer:
+			if err := gob.NewDecoder(bytes.NewReader(body[9:])).Decode(&r.elem); err != nil {
+				return res, off, fmt.Errorf("Segment record can't be decoded: %v", err)
+			}
+		case recPoll:
+		default:
+			return res, off, errors.New("Segment record has an unknown type.")
+		}
+
+		res = append(res, r)
+		off += headerSize + int(n)
+	}
+	return res, off, nil
+}
+
+// validAfter returns true if a whole record which passes its checksum
+// starts anywhere after off. A torn write only leaves garbage at the
+// end of a segment, so such a record means the damage is elsewhere.
+func validAfter(data []byte, off int) bool {
+	for i := off + 1; len(data)-i >= headerSize; i++ {
+		typ := data[i+8]
+		if typ != recOffer && typ != recPoll {
+			continue
+		}
+		n := binary.BigEndian.Uint32(data[i:])
+		if uint64(len(data)-i-headerSize) < uint64(n) {
+			continue
+		}
+		if crc32.ChecksumIEEE(data[i+8:i+headerSize+int(n)]) == binary.BigEndian.Uint32(data[i+4:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,510 @@
+// Package graph provides directed and undirected graphs stored as
+// adjacency lists, with the traversals and path algorithms that are
+// used the most. The walks are built on goods' queue and stack.
+package graph
+
+import (
+	"errors"
+	"math"
+
+	"github.com/emnl/goods/heap"
+	"github.com/emnl/goods/queue"
+	"github.com/emnl/goods/stack"
+)
+
+// A graph knows if it is directed, and keeps its vertices in the order
+// they were added, a map from every vertex to its position, and the
+// edges leaving each vertex, also in the order they were added.
+// Every traversal follows these orders, so its result is predictable.
+//
+// An undirected edge is stored once for each of its ends.
+//
+// e.g. a -> b, a -> c, b -> c:
+//     a: b, c
+//     b: c
+//     c:
+//
+type Graph struct {
+	directed bool
+	vertices []Vertex
+	index    map[Vertex]int
+	adj      [][]edge
+	edges    int
+}
+
+// An edge leads to the vertex at the given position.
+type edge struct {
+	to     int
+	weight float64
+}
+
+// Vertex is used as a generic for any type of comparable value.
+type Vertex interface{}
+
+// Edge is an edge as returned by Edges.
+type Edge struct {
+	From   Vertex
+	To     Vertex
+	Weight float64
+}
+
+// NewDirected is used as a constructor for a directed Graph.
+//
+// e.g. mygraph := graph.NewDirected()
+//
+func NewDirected() *Graph {
+	return &Graph{directed: true, index: map[Vertex]int{}}
+}
+
+// NewUndirected is used as a constructor for an undirected Graph.
+//
+// e.g. mygraph := graph.NewUndirected()
+//
+func NewUndirected() *Graph {
+	return &Graph{directed: false, index: map[Vertex]int{}}
+}
+
+// Directed returns true if the graph is directed.
+func (G *Graph) Directed() bool {
+	return G.directed
+}
+
+// Size returns the number of vertices in the graph.
+//
+// e.g. (a -> b, b -> c).Size() => 3
+//
+func (G *Graph) Size() int {
+	return len(G.vertices)
+}
+
+// Empty returns true if the graph has no vertices.
+//
+// e.g. ().Empty() => true
+//
+func (G *Graph) Empty() bool {
+	return G.Size() == 0
+}
+
+// EdgeCount returns the number of edges in the graph.
+//
+// e.g. (a -> b, b -> c).EdgeCount() => 2
+//
+func (G *Graph) EdgeCount() int {
+	return G.edges
+}
+
+// AddVertex adds a vertex without any edges.
+//
+// e.g. (a -> b).AddVertex(c) => (a -> b, c)
+//
+func (G *Graph) AddVertex(v Vertex) error {
+	if _, ok := G.index[v]; ok {
+		return errors.New("Vertex already exists in Graph.")
+	}
+	G.vertex(v)
+	return nil
+}
+
+// HasVertex returns true if the vertex exists within the graph.
+//
+// e.g. (a -> b).HasVertex(b) => true
+//
+func (G *Graph) HasVertex(v Vertex) bool {
+	_, ok := G.index[v]
+	return ok
+}
+
+// Vertices returns the vertices in the order they were added.
+//
+// e.g. (a -> b, c).Vertices() => [a b c]
+//
+func (G *Graph) Vertices() []Vertex {
+	return append([]Vertex{}, G.vertices...)
+}
+
+// AddEdge adds an edge with weight 1. Vertices that are missing
+// are added first.
+//
+// e.g. (a).AddEdge(a, b) => (a -> b)
+//
+func (G *Graph) AddEdge(from, to Vertex) error {
+	return G.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge adds an edge with the given weight. Vertices that
+// are missing are added first.
+//
+// e.g. (a).AddWeightedEdge(a, b, 2.5) => (a -2.5-> b)
+//
+func (G *Graph) AddWeightedEdge(from, to Vertex, weight float64) error {
+	if G.HasEdge(from, to) {
+		return errors.New("Edge already exists in Graph.")
+	}
+
+	f, t := G.vertex(from), G.vertex(to)
+	G.adj[f] = append(G.adj[f], edge{t, weight})
+	if !G.directed && f != t {
+		G.adj[t] = append(G.adj[t], edge{f, weight})
+	}
+	G.edges++
+	return nil
+}
+
+// RemoveEdge removes an edge. Its vertices stay in the graph.
+//
+// e.g. (a -> b).RemoveEdge(a, b) => (a, b)
+//
+func (G *Graph) RemoveEdge(from, to Vertex) error {
+	if !G.HasEdge(from, to) {
+		return errors.New("Edge not found in Graph.")
+	}
+
+	f, t := G.index[from], G.index[to]
+	G.unlink(f, t)
+	if !G.directed && f != t {
+		G.unlink(t, f)
+	}
+	G.edges--
+	return nil
+}
+
+// HasEdge returns true if there is an edge between the vertices.
+//
+// e.g. (a -> b).HasEdge(a, b) => true
+//      (a -> b).HasEdge(b, a) => false
+//
+func (G *Graph) HasEdge(from, to Vertex) bool {
+	f, ok := G.index[from]
+	t, ok2 := G.index[to]
+	if !ok || !ok2 {
+		return false
+	}
+	for _, e := range G.adj[f] {
+		if e.to == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Weight returns the weight of the edge between the vertices.
+//
+// e.g. (a -2.5-> b).Weight(a, b) => 2.5, nil
+//
+func (G *Graph) Weight(from, to Vertex) (float64, error) {
+	if f, ok := G.index[from]; ok {
+		for _, e := range G.adj[f] {
+			if G.vertices[e.to] == to {
+				return e.weight, nil
+			}
+		}
+	}
+	return 0, errors.New("Edge not found in Graph.")
+}
+
+// Neighbors returns the vertices that the edges leaving the given
+// vertex lead to, in the order the edges were added.
+//
+// e.g. (a -> b, a -> c).Neighbors(a) => [b c]
+//
+func (G *Graph) Neighbors(v Vertex) []Vertex {
+	res := []Vertex{}
+	if i, ok := G.index[v]; ok {
+		for _, e := range G.adj[i] {
+			res = append(res, G.vertices[e.to])
+		}
+	}
+	return res
+}
+
+// Edges returns every edge of the graph. An undirected
+// edge is only returned once.
+//
+// e.g. (a -> b, b -> c).Edges() => [{a b 1} {b c 1}]
+//
+func (G *Graph) Edges() []Edge {
+	res := make([]Edge, 0, G.edges)
+	for f, edges := range G.adj {
+		for _, e := range edges {
+			if G.directed || f <= e.to {
+				res = append(res, Edge{G.vertices[f], G.vertices[e.to], e.weight})
+			}
+		}
+	}
+	return res
+}
+
+// BFS returns an iterator over the vertices reachable from the
+// start, in breadth-first order.
+//
+// e.g. for v := range (a -> b, a -> c, b -> d).BFS(a) { v } => a, b, c, d
+//
+func (G *Graph) BFS(start Vertex) chan Vertex {
+	res := []Vertex{}
+	if s, ok := G.index[start]; ok {
+		visited := make([]bool, len(G.vertices))
+		visited[s] = true
+
+		nodes := queue.New()
+		nodes.Offer(s)
+		for !nodes.Empty() {
+			i := nodes.Poll().(int)
+			res = append(res, G.vertices[i])
+
+			for _, e := range G.adj[i] {
+				if !visited[e.to] {
+					visited[e.to] = true
+					nodes.Offer(e.to)
+				}
+			}
+		}
+	}
+	return iter(res)
+}
+
+// DFS returns an iterator over the vertices reachable from the
+// start, in depth-first order.
+//
+// e.g. for v := range (a -> b, a -> c, b -> d).DFS(a) { v } => a, b, d, c
+//
+func (G *Graph) DFS(start Vertex) chan Vertex {
+	res := []Vertex{}
+	if s, ok := G.index[start]; ok {
+		visited := make([]bool, len(G.vertices))
+
+		nodes := stack.New()
+		nodes.Push(s)
+		for !nodes.Empty() {
+			i := nodes.Pop().(int)
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+			res = append(res, G.vertices[i])
+
+			/* Push the neighbors backwards, so the first one is visited first */
+			for j := len(G.adj[i]) - 1; j >= 0; j-- {
+				if to := G.adj[i][j].to; !visited[to] {
+					nodes.Push(to)
+				}
+			}
+		}
+	}
+	return iter(res)
+}
+
+// TopologicalSort returns the vertices of a directed graph ordered so
+// that every edge leads forward. Among the vertices that could come
+// next, the one added first is chosen.
+//
+// e.g. (a -> c, b -> a).TopologicalSort() => [b a c]
+//
+func (G *Graph) TopologicalSort() ([]Vertex, error) {
+	if !G.directed {
+		return nil, errors.New("Graph is not directed.")
+	}
+
+	incoming := make([]int, len(G.vertices))
+	for _, edges := range G.adj {
+		for _, e := range edges {
+			incoming[e.to]++
+		}
+	}
+
+	/* The ready vertices are taken in the order they were added */
+	ready := heap.New(func(a, b interface{}) bool { return a.(int) < b.(int) })
+	for i, n := range incoming {
+		if n == 0 {
+			ready.Push(i)
+		}
+	}
+
+	res := make([]Vertex, 0, len(G.vertices))
+	for !ready.Empty() {
+		i := ready.Pop().(int)
+		res = append(res, G.vertices[i])
+
+		for _, e := range G.adj[i] {
+			incoming[e.to]--
+			if incoming[e.to] == 0 {
+				ready.Push(e.to)
+			}
+		}
+	}
+
+	if len(res) < len(G.vertices) {
+		return nil, errors.New("Graph contains a cycle.")
+	}
+	return res, nil
+}
+
+// Dijkstra returns the length of the shortest path from the source to
+// every vertex it reaches, and the vertex before each of them on that
+// path. The weights must not be negative.
+//
+// e.g. (a -1-> b, b -1-> c, a -5-> c).Dijkstra(a)
+//      => {a:0 b:1 c:2}, {b:a c:b}, nil
+//
+func (G *Graph) Dijkstra(source Vertex) (map[Vertex]float64, map[Vertex]Vertex, error) {
+	s, ok := G.index[source]
+	if !ok {
+		return nil, nil, errors.New("Vertex not found in Graph.")
+	}
+
+	dist := make([]float64, len(G.vertices))
+	prev := make([]int, len(G.vertices))
+	for i := range dist {
+		dist[i], prev[i] = math.Inf(1), -1
+	}
+	dist[s] = 0
+
+	/* A vertex may be queued more than once, the stale entries are skipped */
+	type entry struct {
+		v    int
+		dist float64
+	}
+	pending := heap.New(func(a, b interface{}) bool { return a.(entry).dist < b.(entry).dist })
+	pending.Push(entry{s, 0})
+
+	for !pending.Empty() {
+		cur := pending.Pop().(entry)
+		if cur.dist > dist[cur.v] {
+			continue
+		}
+
+		for _, e := range G.adj[cur.v] {
+			if e.weight < 0 {
+				return nil, nil, errors.New("Graph has a negative edge weight.")
+			}
+			if d := cur.dist + e.weight; d < dist[e.to] {
+				dist[e.to], prev[e.to] = d, cur.v
+				pending.Push(entry{e.to, d})
+			}
+		}
+	}
+
+	distances, previous := map[Vertex]float64{}, map[Vertex]Vertex{}
+	for i, d := range dist {
+		if !math.IsInf(d, 1) {
+			distances[G.vertices[i]] = d
+		}
+		if prev[i] >= 0 {
+			previous[G.vertices[i]] = G.vertices[prev[i]]
+		}
+	}
+	return distances, previous, nil
+}
+
+// ShortestPath returns the vertices on the shortest path between two
+// vertices, both included, and the length of the path.
+//
+// e.g. (a -1-> b, b -1-> c, a -5-> c).ShortestPath(a, c) => [a b c], 2, nil
+//
+func (G *Graph) ShortestPath(from, to Vertex) ([]Vertex, float64, error) {
+	if !G.HasVertex(to) {
+		return nil, 0, errors.New("Vertex not found in Graph.")
+	}
+
+	dist, prev, err := G.Dijkstra(from)
+	if err != nil {
+		return nil, 0, err
+	}
+	d, ok := dist[to]
+	if !ok {
+		return nil, 0, errors.New("No path between the vertices.")
+	}
+
+	path := []Vertex{to}
+	for v := to; v != from; {
+		v = prev[v]
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, d, nil
+}
+
+// ConnectedComponents returns the groups of vertices that are connected
+// to each other, ignoring the direction of the edges. The groups, and
+// the vertices in them, follow the order the vertices were added.
+//
+// e.g. (a - b, c, d - a).ConnectedComponents() => [[a b d] [c]]
+//
+func (G *Graph) ConnectedComponents() [][]Vertex {
+	/* Follow the edges both ways */
+	links := make([][]int, len(G.vertices))
+	for f, edges := range G.adj {
+		for _, e := range edges {
+			links[f] = append(links[f], e.to)
+			if G.directed {
+				links[e.to] = append(links[e.to], f)
+			}
+		}
+	}
+
+	component := make([]int, len(G.vertices))
+	for i := range component {
+		component[i] = -1
+	}
+
+	res := [][]Vertex{}
+	for s := range G.vertices {
+		if component[s] >= 0 {
+			continue
+		}
+
+		c := len(res)
+		component[s] = c
+		nodes := queue.New()
+		nodes.Offer(s)
+		for !nodes.Empty() {
+			i := nodes.Poll().(int)
+			for _, to := range links[i] {
+				if component[to] < 0 {
+					component[to] = c
+					nodes.Offer(to)
+				}
+			}
+		}
+		res = append(res, []Vertex{})
+	}
+
+	for i, c := range component {
+		res[c] = append(res[c], G.vertices[i])
+	}
+	return res
+}
+
+// vertex returns the position of the vertex, and
+// adds the vertex first if it is missing.
+func (G *Graph) vertex(v Vertex) int {
+	if i, ok := G.index[v]; ok {
+		return i
+	}
+	G.index[v] = len(G.vertices)
+	G.vertices = append(G.vertices, v)
+	G.adj = append(G.adj, nil)
+	return len(G.vertices) - 1
+}
+
+// unlink removes the edge from f to t.
+func (G *Graph) unlink(f, t int) {
+	edges := G.adj[f]
+	for j, e := range edges {
+		if e.to == t {
+			G.adj[f] = append(edges[:j], edges[j+1:]...)
+			return
+		}
+	}
+}
+
+// iter returns an iterator over the given vertices.
+func iter(res []Vertex) chan Vertex {
+	ch := make(chan Vertex, len(res))
+	for _, v := range res {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+// collect returns the vertices of the iterator as a string.
+func collect(ch chan Vertex) string {
+	res := []Vertex{}
+	for v := range ch {
+		res = append(res, v)
+	}
+	return fmt.Sprint(res)
+}
+
+func TestNew(t *testing.T) {
+	d, u := NewDirected(), NewUndirected()
+
+	if !d.Directed() || u.Directed() || !d.Empty() || u.Size() != 0 || d.EdgeCount() != 0 {
+		t.Errorf("The constructors are broken.")
+	}
+}
+
+func TestVertices(t *testing.T) {
+	g := NewDirected()
+	if g.AddVertex("a") != nil || g.AddVertex("b") != nil {
+		t.Errorf("AddVertex should accept a new vertex.")
+	}
+	if g.AddVertex("a") == nil {
+		t.Errorf("AddVertex should refuse a vertex that already exists.")
+	}
+	g.AddEdge("c", "a")
+
+	if fmt.Sprint(g.Vertices()) != "[a b c]" || !g.HasVertex("c") || g.HasVertex("d") {
+		t.Errorf("The vertices should be kept in the order they were added.")
+	}
+}
+
+func TestEdges(t *testing.T) {
+	d, u := NewDirected(), NewUndirected()
+	for _, g := range []*Graph{d, u} {
+		if g.AddWeightedEdge("a", "b", 2) != nil || g.AddEdge("a", "c") != nil {
+			t.Errorf("AddEdge should accept a new edge.")
+		}
+		if g.AddEdge("a", "b") == nil {
+			t.Errorf("AddEdge should refuse an edge that already exists.")
+		}
+		if w, err := g.Weight("a", "b"); err != nil || w != 2 {
+			t.Errorf("Weight is broken.")
+		}
+		if g.EdgeCount() != 2 || len(g.Edges()) != 2 {
+			t.Errorf("AddEdge should add edges.")
+		}
+	}
+
+	if d.HasEdge("b", "a") || !u.HasEdge("b", "a") {
+		t.Errorf("Only undirected edges should go both ways.")
+	}
+	if u.AddEdge("b", "a") == nil {
+		t.Errorf("An undirected edge should exist both ways.")
+	}
+	if fmt.Sprint(u.Neighbors("b")) != "[a]" || fmt.Sprint(d.Neighbors("a")) != "[b c]" {
+		t.Errorf("Neighbors is broken.")
+	}
+
+	if u.RemoveEdge("b", "a") != nil || u.HasEdge("a", "b") || u.EdgeCount() != 1 {
+		t.Errorf("RemoveEdge should remove both ways of an undirected edge.")
+	}
+	if d.RemoveEdge("b", "a") == nil {
+		t.Errorf("RemoveEdge should refuse an edge that doesn't exist.")
+	}
+}
+
+func TestTraversals(t *testing.T) {
+	g := NewDirected()
+	g.AddEdge("a", "b")
+	g.AddEdge("a", "c")
+	g.AddEdge("b", "d")
+	g.AddEdge("c", "d")
+	g.AddEdge("d", "a")
+	g.AddEdge("e", "a")
+
+	if got := collect(g.BFS("a")); got != "[a b c d]" {
+		t.Errorf("BFS is broken, got %v.", got)
+	}
+	if got := collect(g.DFS("a")); got != "[a b d c]" {
+		t.Errorf("DFS is broken, got %v.", got)
+	}
+	if got := collect(g.BFS("x")); got != "[]" {
+		t.Errorf("BFS from a missing vertex should be empty.")
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := NewDirected()
+	g.AddEdge("app", "lib")
+	g.AddEdge("lib", "core")
+	g.AddEdge("test", "app")
+	g.AddEdge("app", "core")
+	g.AddVertex("docs")
+
+	order, err := g.TopologicalSort()
+	if err != nil || fmt.Sprint(order) != "[test app lib core docs]" {
+		t.Errorf("TopologicalSort is broken, got %v.", order)
+	}
+
+	g.AddEdge("core", "test")
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Errorf("TopologicalSort should detect a cycle.")
+	}
+	if _, err := NewUndirected().TopologicalSort(); err == nil {
+		t.Errorf("TopologicalSort should refuse an undirected graph.")
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	g := NewUndirected()
+	g.AddWeightedEdge("a", "b", 7)
+	g.AddWeightedEdge("a", "c", 9)
+	g.AddWeightedEdge("a", "f", 14)
+	g.AddWeightedEdge("b", "c", 10)
+	g.AddWeightedEdge("b", "d", 15)
+	g.AddWeightedEdge("c", "d", 11)
+	g.AddWeightedEdge("c", "f", 2)
+	g.AddWeightedEdge("d", "e", 6)
+	g.AddWeightedEdge("e", "f", 9)
+	g.AddVertex("x")
+
+	dist, _, err := g.Dijkstra("a")
+	if err != nil || dist["e"] != 20 || dist["d"] != 20 || dist["f"] != 11 {
+		t.Errorf("Dijkstra is broken, got %v.", dist)
+	}
+	if _, ok := dist["x"]; ok {
+		t.Errorf("Dijkstra should leave out unreachable vertices.")
+	}
+
+	path, d, err := g.ShortestPath("a", "e")
+	if err != nil || d != 20 || fmt.Sprint(path) != "[a c f e]" {
+		t.Errorf("ShortestPath is broken, got %v %v.", path, d)
+	}
+	if path, _, _ := g.ShortestPath("a", "a"); fmt.Sprint(path) != "[a]" {
+		t.Errorf("ShortestPath to the source should be the source.")
+	}
+	if _, _, err := g.ShortestPath("a", "x"); err == nil {
+		t.Errorf("ShortestPath should fail without a path.")
+	}
+
+	g.AddWeightedEdge("x", "a", -1)
+	if _, _, err := g.Dijkstra("x"); err == nil {
+		t.Errorf("Dijkstra should refuse a negative weight.")
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	g := NewDirected()
+	g.AddEdge("a", "b")
+	g.AddVertex("c")
+	g.AddEdge("d", "a")
+	g.AddEdge("e", "f")
+
+	if got := fmt.Sprint(g.ConnectedComponents()); got != "[[a b d] [c] [e f]]" {
+		t.Errorf("ConnectedComponents is broken, got %v.", got)
+	}
+}
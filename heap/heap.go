@@ -0,0 +1,286 @@
+// Package heap provides a d-ary heap which gives the smallest
+// element, as decided by a user defined function, in O(1) and
+// removes it in O(log n). It makes a good priority queue.
+package heap
+
+import "reflect"
+
+// A heap has a user defined function which is used to compare the
+// elements, the number of children per node, and the elements.
+//
+// The elements are stored level by level in a slice, so the children
+// of the element at i are found at d*i+1 to d*i+d. No element is less
+// than its parent, which makes the first element the smallest.
+//
+// e.g. binary heap (1,3,2,7,4):
+//           1
+//         /   \
+//        3     2
+//       / \
+//      7   4
+//
+type Heap struct {
+	less  LessFunc
+	d     int
+	elems []Elem
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// LessFunc is used as a user function to compare elements in the heap.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// New is used as a constructor for a binary Heap. A LessFunc that
+// returns a > b makes a max-heap.
+//
+// e.g. myheap := heap.New(intLess)
+//
+func New(lf LessFunc) *Heap {
+	return NewDary(2, lf)
+}
+
+// NewDary is used as a constructor for a Heap where every node has
+// d children. A wider heap is shallower, so Push is faster while Pop
+// compares more children on each level. A d below 2 is raised to 2.
+//
+// e.g. myheap := heap.NewDary(4, intLess)
+//
+func NewDary(d int, lf LessFunc) *Heap {
+	if d < 2 {
+		d = 2
+	}
+	return &Heap{lf, d, nil}
+}
+
+// Size returns the size of the heap.
+//
+// e.g. (1,3,2).Size() => 3
+//
+func (H *Heap) Size() int {
+	return len(H.elems)
+}
+
+// Len is an alias for Size().
+func (H *Heap) Len() int {
+	return H.Size()
+}
+
+// Empty returns true if the heap is empty.
+//
+// e.g. ().Empty() => true
+//
+func (H *Heap) Empty() bool {
+	return H.Size() == 0
+}
+
+// Push adds an element to the heap.
+//
+// e.g. (1,3).Push(2) => (1,3,2)
+//
+func (H *Heap) Push(E Elem) {
+	H.elems = append(H.elems, E)
+	H.up(len(H.elems) - 1)
+}
+
+// Pop removes the smallest element from the heap and returns it.
+//
+// e.g. (1,3,2).Pop() => 1
+//       --^-- .Pop() => 2
+//
+func (H *Heap) Pop() Elem {
+	if H.Empty() {
+		return nil
+	}
+
+	top := H.elems[0]
+	last := len(H.elems) - 1
+	H.elems[0] = H.elems[last]
+	H.elems[last] = nil
+	H.elems = H.elems[:last]
+	if last > 0 {
+		H.down(0)
+	}
+	return top
+}
+
+// Peek returns the smallest element without removing it.
+//
+// e.g. (1,3,2).Peek() => 1
+//       --^-- .Peek() => 1
+//
+func (H *Heap) Peek() Elem {
+	if H.Empty() {
+		return nil
+	}
+	return H.elems[0]
+}
+
+// PushPop adds an element and then removes the smallest one, which
+// may be the new element. It is faster than Push followed by Pop.
+//
+// e.g. (2,3).PushPop(1) => 1
+//       (2,3).PushPop(4) => 2, leaving (3,4)
+//
+func (H *Heap) PushPop(E Elem) Elem {
+	if H.Empty() || !H.less(H.elems[0], E) {
+		return E
+	}
+
+	top := H.elems[0]
+	H.elems[0] = E
+	H.down(0)
+	return top
+}
+
+// Replace removes the smallest element and then adds the new one,
+// so the result is never the new element. It is faster than Pop
+// followed by Push. It returns nil if the heap was empty.
+//
+// e.g. (2,3).Replace(1) => 2, leaving (1,3)
+//
+func (H *Heap) Replace(E Elem) Elem {
+	if H.Empty() {
+		H.Push(E)
+		return nil
+	}
+
+	top := H.elems[0]
+	H.elems[0] = E
+	H.down(0)
+	return top
+}
+
+// Heapify replaces the elements of the heap with the elements
+// of a go slice. It runs in O(n), rather than O(n log n) for
+// pushing them one by one.
+//
+// e.g. ().Heapify([]int{3, 1, 2}) => (1,3,2)
+//
+func (H *Heap) Heapify(slc interface{}) {
+	v := reflect.ValueOf(slc)
+	H.elems = make([]Elem, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		H.elems[i] = v.Index(i).Interface()
+	}
+	H.build()
+}
+
+// Merge adds every element of the other heap to the heap, and
+// leaves the other heap untouched. It runs in O(min(n+m, m log n)).
+//
+// e.g. (1,3).Merge((2,4)) => (1,2,3,4)
+//
+func (H *Heap) Merge(other *Heap) {
+	if len(other.elems) < len(H.elems)/H.d {
+		/* Pushing a few elements is cheaper than a rebuild */
+		for _, E := range other.elems {
+			H.Push(E)
+		}
+		return
+	}
+
+	H.elems = append(H.elems, other.elems...)
+	H.build()
+}
+
+// TopK returns the k smallest elements in order, without removing
+// them. It runs in O(k log k) however big the heap is.
+//
+// e.g. (1,3,2,7,4).TopK(3) => [1 2 3]
+//
+func (H *Heap) TopK(k int) []Elem {
+	if k > len(H.elems) {
+		k = len(H.elems)
+	}
+	res := make([]Elem, 0, k)
+	if k <= 0 {
+		return res
+	}
+
+	/* The candidates are positions whose parents have been taken */
+	candidates := &Heap{func(a, b interface{}) bool {
+		return H.less(H.elems[a.(int)], H.elems[b.(int)])
+	}, H.d, []Elem{0}}
+
+	for len(res) < k {
+		i := candidates.Pop().(int)
+		res = append(res, H.elems[i])
+
+		for c := H.d*i + 1; c <= H.d*i+H.d && c < len(H.elems); c++ {
+			candidates.Push(c)
+		}
+	}
+	return res
+}
+
+// Drain returns an iterator over the elements, smallest first,
+// and removes them from the heap.
+//
+// e.g. for x := range (1,3,2).Drain() { x } => 1, 2, 3
+//
+func (H *Heap) Drain() chan Elem {
+	ch := make(chan Elem, len(H.elems))
+	for !H.Empty() {
+		ch <- H.Pop()
+	}
+	close(ch)
+	return ch
+}
+
+// build restores the order of the whole heap, starting with
+// the last element that has a child.
+func (H *Heap) build() {
+	if len(H.elems) < 2 {
+		return
+	}
+	for i := (len(H.elems) - 2) / H.d; i >= 0; i-- {
+		H.down(i)
+	}
+}
+
+// up moves the element at i up until its parent is not bigger.
+func (H *Heap) up(i int) {
+	E := H.elems[i]
+	for i > 0 {
+		parent := (i - 1) / H.d
+		if !H.less(E, H.elems[parent]) {
+			break
+		}
+		H.elems[i] = H.elems[parent]
+		i = parent
+	}
+	H.elems[i] = E
+}
+
+// down moves the element at i down until none
+// of its children is smaller.
+func (H *Heap) down(i int) {
+	E := H.elems[i]
+	for {
+		first := H.d*i + 1
+		if first >= len(H.elems) {
+			break
+		}
+
+		min := first
+		for c := first + 1; c < first+H.d && c < len(H.elems); c++ {
+			if H.less(H.elems[c], H.elems[min]) {
+				min = c
+			}
+		}
+		if !H.less(H.elems[min], E) {
+			break
+		}
+
+		H.elems[i] = H.elems[min]
+		i = min
+	}
+	H.elems[i] = E
+}
@@ -0,0 +1,157 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// checkHeap reports every element that is less than its parent.
+func checkHeap(t *testing.T, heap *Heap) {
+	for i := 1; i < len(heap.elems); i++ {
+		if heap.less(heap.elems[i], heap.elems[(i-1)/heap.d]) {
+			t.Errorf("Element %d is less than its parent.", i)
+		}
+	}
+}
+
+// drain returns the elements of the heap in the order they pop.
+func drain(heap *Heap) []int {
+	res := []int{}
+	for x := range heap.Drain() {
+		res = append(res, x.(int))
+	}
+	return res
+}
+
+func TestNew(t *testing.T) {
+	heap := New(intLess)
+
+	if heap.Size() != 0 || !heap.Empty() || heap.Peek() != nil || heap.Pop() != nil {
+		t.Errorf("New constructor is broken.")
+	}
+	if NewDary(1, intLess).d != 2 || NewDary(4, intLess).d != 4 {
+		t.Errorf("NewDary constructor is broken.")
+	}
+}
+
+func TestPushPop(t *testing.T) {
+	for d := 2; d <= 5; d++ {
+		heap := NewDary(d, intLess)
+		perm := rand.New(rand.NewSource(int64(d))).Perm(300)
+		for _, x := range perm {
+			heap.Push(x)
+		}
+		checkHeap(t, heap)
+
+		if heap.Size() != 300 || heap.Peek() != 0 {
+			t.Errorf("Push should add elements.")
+		}
+		for i := 0; i < 300; i++ {
+			if x := heap.Pop(); x != i {
+				t.Fatalf("Pop should return the smallest element, got %v want %d.", x, i)
+			}
+		}
+		if !heap.Empty() {
+			t.Errorf("Pop should remove elements.")
+		}
+	}
+}
+
+func TestPushPopMethod(t *testing.T) {
+	heap := New(intLess)
+	if heap.PushPop(5) != 5 || !heap.Empty() {
+		t.Errorf("PushPop on an empty heap should return the element.")
+	}
+
+	heap.Heapify([]int{2, 3})
+	if heap.PushPop(1) != 1 || heap.Size() != 2 {
+		t.Errorf("PushPop should return a new smallest element.")
+	}
+	if heap.PushPop(4) != 2 || heap.Peek() != 3 || heap.Size() != 2 {
+		t.Errorf("PushPop should return the old smallest element.")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	heap := New(intLess)
+	if heap.Replace(5) != nil || heap.Size() != 1 {
+		t.Errorf("Replace on an empty heap should push the element.")
+	}
+
+	heap.Heapify([]int{2, 3})
+	if heap.Replace(1) != 2 || heap.Peek() != 1 || heap.Size() != 2 {
+		t.Errorf("Replace should pop before it pushes.")
+	}
+}
+
+func TestHeapify(t *testing.T) {
+	for d := 2; d <= 4; d++ {
+		for n := 0; n < 40; n++ {
+			heap := NewDary(d, intLess)
+			heap.Heapify(rand.New(rand.NewSource(int64(n))).Perm(n))
+			checkHeap(t, heap)
+
+			res := drain(heap)
+			if len(res) != n || !sort.IntsAreSorted(res) {
+				t.Errorf("Heapify should keep every element in heap order.")
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	for _, sizes := range [][2]int{{0, 0}, {10, 0}, {0, 10}, {100, 3}, {10, 100}} {
+		a, b := New(intLess), New(intLess)
+		for i := 0; i < sizes[0]; i++ {
+			a.Push(2 * i)
+		}
+		for i := 0; i < sizes[1]; i++ {
+			b.Push(2*i + 1)
+		}
+
+		a.Merge(b)
+		checkHeap(t, a)
+		if a.Size() != sizes[0]+sizes[1] || b.Size() != sizes[1] {
+			t.Errorf("Merge should add every element of the other heap.")
+		}
+		if res := drain(a); !sort.IntsAreSorted(res) {
+			t.Errorf("Merge should keep the heap order.")
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	heap := NewDary(3, intLess)
+	heap.Heapify(rand.New(rand.NewSource(1)).Perm(100))
+
+	top := heap.TopK(10)
+	if len(top) != 10 {
+		t.Fatalf("TopK should return k elements.")
+	}
+	for i, x := range top {
+		if x != i {
+			t.Errorf("TopK should return the smallest elements in order, got %v.", top)
+			break
+		}
+	}
+	if heap.Size() != 100 {
+		t.Errorf("TopK should not remove elements.")
+	}
+	if len(heap.TopK(200)) != 100 || len(heap.TopK(0)) != 0 {
+		t.Errorf("TopK should return at most the whole heap.")
+	}
+}
+
+func TestMaxHeap(t *testing.T) {
+	heap := New(func(a, b interface{}) bool { return a.(int) > b.(int) })
+	heap.Heapify([]int{3, 9, 1, 5})
+
+	if heap.Pop() != 9 || heap.Pop() != 5 {
+		t.Errorf("A reversed LessFunc should make a max-heap.")
+	}
+}
@@ -0,0 +1,233 @@
+// Package intervaltree provides an interval tree for overlap
+// queries. It is built on an augmented red-black tree.
+package intervaltree
+
+import (
+	"errors"
+	"github.com/emnl/goods/redblacktree"
+)
+
+// An intervaltree has a user defined function which is used to compare
+// the endpoints of the intervals, and a redblacktree holding the intervals.
+//
+// The intervals are ordered by their low endpoint, and every node of the
+// redblacktree is augmented with a heap of the intervals in its subtree,
+// ordered by their high endpoint. The top of the heap is the biggest high
+// endpoint in the subtree. The heaps are persistent, so a node shares
+// most of its heap with its children: an update builds O(log n) new heap
+// nodes for each of the O(log n) nodes it touches, and the tree takes
+// O(n log n) memory.
+type IntervalTree struct {
+	less LessFunc
+	tree *redblacktree.RedBlackTree
+}
+
+// Interval is a closed interval [Lo, Hi] with a value attached.
+type Interval struct {
+	Lo    Elem
+	Hi    Elem
+	Value interface{}
+}
+
+// Elem is used as a generic for any type of endpoint.
+type Elem interface{}
+
+// LessFunc is used as a user function to compare endpoints.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// New is used as a constructor for the IntervalTree
+// struct.
+//
+// e.g. mytree := intervaltree.New(intLess)
+//
+func New(lf LessFunc) *IntervalTree {
+	T := &IntervalTree{less: lf}
+	T.tree = redblacktree.NewAugmented(T.intervalLess, T.maxHi)
+	return T
+}
+
+// Size returns the number of intervals in the tree.
+//
+// e.g. ([1,3] [2,4]).Size() => 2
+//
+func (T *IntervalTree) Size() int {
+	return T.tree.Size()
+}
+
+// Empty returns true if the tree holds no intervals.
+//
+// e.g. ().Empty() => true
+//
+func (T *IntervalTree) Empty() bool {
+	return T.tree.Empty()
+}
+
+// Insert adds the interval [lo, hi] with the given value.
+// Two intervals with the same endpoints can't be held at once.
+//
+// e.g. ([1,3]).Insert(2, 4, "b") => ([1,3] [2,4])
+//
+func (T *IntervalTree) Insert(lo, hi Elem, V interface{}) error {
+	if T.less(hi, lo) {
+		return errors.New("Interval ends before it starts.")
+	}
+
+	i := Interval{lo, hi, V}
+	if T.tree.Contains(i) {
+		return errors.New("Interval already exists in Tree.")
+	}
+	return T.tree.Add(i)
+}
+
+// Delete removes the interval [lo, hi].
+//
+// e.g. ([1,3] [2,4]).Delete(1, 3) => ([2,4])
+//
+func (T *IntervalTree) Delete(lo, hi Elem) error {
+	if T.tree.Remove(Interval{lo, hi, nil}) != nil {
+		return errors.New("Interval not found in Tree.")
+	}
+	return nil
+}
+
+// Stabbing returns every interval that contains the given point,
+// in no particular order. It runs in O(log n + k) for k results.
+//
+// e.g. ([1,3] [2,4] [5,6]).Stabbing(3) => [1,3] [2,4]
+//
+func (T *IntervalTree) Stabbing(point Elem) []Interval {
+	return T.Overlapping(point, point)
+}
+
+// Overlapping returns every interval that overlaps [lo, hi],
+// in no particular order. It runs in O(log n + k) for k results.
+//
+// e.g. ([1,3] [2,4] [5,6]).Overlapping(4, 5) => [2,4] [5,6]
+//
+func (T *IntervalTree) Overlapping(lo, hi Elem) []Interval {
+	res := []Interval{}
+	T.search(lo, hi, func(i Interval) bool {
+		res = append(res, i)
+		return true
+	})
+	return res
+}
+
+// AnyOverlap returns true if any interval overlaps [lo, hi].
+// It runs in O(log n).
+//
+// e.g. ([1,3] [5,6]).AnyOverlap(4, 4) => false
+//
+func (T *IntervalTree) AnyOverlap(lo, hi Elem) bool {
+	found := false
+	T.search(lo, hi, func(i Interval) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// search calls f for every interval that overlaps [lo, hi], until f
+// returns false.
+//
+// The intervals which start at or before hi are the nodes on the path
+// down to hi, and the left subtrees of the nodes where the path turns
+// right. Of those, the ones which end at or after lo overlap, and each
+// heap gives them up in O(1) per result.
+func (T *IntervalTree) search(lo, hi Elem, f func(Interval) bool) {
+	for c := T.tree.Root(); !c.Empty(); {
+		i := c.Elem().(Interval)
+		if T.less(hi, i.Lo) {
+			c = c.Left()
+			continue
+		}
+
+		if !T.report(heapOf(c.Left()), lo, f) {
+			return
+		}
+		if !T.less(i.Hi, lo) && !f(i) {
+			return
+		}
+		c = c.Right()
+	}
+}
+
+// report calls f for every interval in the heap that ends at or
+// after lo, until f returns false. It returns false if it was stopped.
+func (T *IntervalTree) report(h *heap, lo Elem, f func(Interval) bool) bool {
+	if h == nil || T.less(h.top.Hi, lo) {
+		return true // Every interval in the heap ends before lo
+	}
+	return f(h.top) && T.report(h.left, lo, f) && T.report(h.right, lo, f)
+}
+
+// intervalLess orders the intervals by their low
+// endpoint, and then by their high endpoint.
+func (T *IntervalTree) intervalLess(a, b interface{}) bool {
+	x, y := a.(Interval), b.(Interval)
+	switch {
+	case T.less(x.Lo, y.Lo):
+		return true
+	case T.less(y.Lo, x.Lo):
+		return false
+	}
+	return T.less(x.Hi, y.Hi)
+}
+
+// maxHi augments the nodes of the redblacktree with a heap
+// of the intervals in their subtree, whose top has the
+// biggest high endpoint.
+func (T *IntervalTree) maxHi(E redblacktree.Elem, left, right interface{}) interface{} {
+	l, _ := left.(*heap)
+	r, _ := right.(*heap)
+	return T.meld(T.meld(l, r), &heap{top: E.(Interval), rank: 1})
+}
+
+// heap is a node of a persistent leftist heap of intervals, ordered
+// by their high endpoint. A heap is never changed once built, so the
+// heaps of a node's children may be shared with its own.
+type heap struct {
+	top         Interval
+	left, right *heap
+	rank        int // length of the right spine
+}
+
+// heapOf returns the heap of the subtree, or nil if it's empty.
+func heapOf(c redblacktree.Cursor) *heap {
+	h, _ := c.Augmented().(*heap)
+	return h
+}
+
+// rankOf returns the rank of the heap, 0 if it's empty.
+func rankOf(h *heap) int {
+	if h == nil {
+		return 0
+	}
+	return h.rank
+}
+
+// meld returns a heap with the intervals of both heaps, leaving
+// them untouched. It builds O(log n) new nodes along the right
+// spines.
+func (T *IntervalTree) meld(a, b *heap) *heap {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if T.less(a.top.Hi, b.top.Hi) {
+		a, b = b, a
+	}
+
+	l, r := a.left, T.meld(a.right, b)
+	if rankOf(l) < rankOf(r) {
+		l, r = r, l
+	}
+	return &heap{a.top, l, r, rankOf(r) + 1}
+}
@@ -0,0 +1,121 @@
+package intervaltree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+func TestNew(t *testing.T) {
+	tree := New(intLess)
+
+	if tree.Size() != 0 || !tree.Empty() {
+		t.Errorf("New constructor is broken.")
+	}
+}
+
+func TestInsert(t *testing.T) {
+	tree := New(intLess)
+
+	if tree.Insert(1, 3, "a") != nil || tree.Insert(1, 4, "b") != nil {
+		t.Errorf("Insert should add intervals.")
+	}
+	if tree.Insert(1, 3, "c") == nil {
+		t.Errorf("Insert should refuse an interval that already exists.")
+	}
+	if tree.Insert(5, 4, "d") == nil {
+		t.Errorf("Insert should refuse an interval that ends before it starts.")
+	}
+	if tree.Size() != 2 {
+		t.Errorf("Size should return 2.")
+	}
+	for _, x := range tree.Stabbing(2) {
+		if x.Value == "c" {
+			t.Errorf("Insert should not replace an existing interval.")
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tree := New(intLess)
+	tree.Insert(1, 3, nil)
+	tree.Insert(2, 4, nil)
+
+	if tree.Delete(1, 4) == nil {
+		t.Errorf("Delete should refuse an interval that doesn't exist.")
+	}
+	if tree.Delete(1, 3) != nil || tree.Size() != 1 {
+		t.Errorf("Delete should remove the interval.")
+	}
+	if len(tree.Stabbing(1)) != 0 {
+		t.Errorf("A deleted interval should not be found.")
+	}
+}
+
+func TestStabbing(t *testing.T) {
+	tree := New(intLess)
+	tree.Insert(1, 3, "a")
+	tree.Insert(2, 4, "b")
+	tree.Insert(5, 6, "c")
+
+	res := tree.Stabbing(3)
+	if len(res) != 2 || res[0].Value == res[1].Value || res[0].Value == "c" || res[1].Value == "c" {
+		t.Errorf("Stabbing should return the intervals that contain the point.")
+	}
+	if len(tree.Stabbing(0)) != 0 || len(tree.Stabbing(7)) != 0 {
+		t.Errorf("Stabbing should return nothing outside the intervals.")
+	}
+	if len(tree.Stabbing(6)) != 1 {
+		t.Errorf("Stabbing should treat the intervals as closed.")
+	}
+}
+
+func TestOverlapping(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := New(intLess)
+	intervals := [][2]int{}
+
+	for i := 0; i < 300; i++ {
+		lo := r.Intn(1000)
+		hi := lo + r.Intn(50)
+		if tree.Insert(lo, hi, i) == nil {
+			intervals = append(intervals, [2]int{lo, hi})
+		}
+	}
+
+	/* The heaps must follow the deletes as well */
+	for i := 0; i < len(intervals); i += 3 {
+		tree.Delete(intervals[i][0], intervals[i][1])
+		intervals[i] = [2]int{-1, -1}
+	}
+
+	for i := 0; i < 100; i++ {
+		lo := r.Intn(1000)
+		hi := lo + r.Intn(30)
+
+		exp := 0
+		for _, x := range intervals {
+			if x[0] <= hi && lo <= x[1] {
+				exp++
+			}
+		}
+
+		res := tree.Overlapping(lo, hi)
+		if len(res) != exp {
+			t.Errorf("Overlapping(%d, %d) should find %d intervals, found %d.", lo, hi, exp, len(res))
+		}
+		seen := map[interface{}]bool{}
+		for _, x := range res {
+			if seen[x.Value] || hi < x.Lo.(int) || x.Hi.(int) < lo {
+				t.Errorf("Overlapping(%d, %d) found a wrong interval %v.", lo, hi, x)
+			}
+			seen[x.Value] = true
+		}
+		if tree.AnyOverlap(lo, hi) != (exp > 0) {
+			t.Errorf("AnyOverlap(%d, %d) should be %v.", lo, hi, exp > 0)
+		}
+	}
+}
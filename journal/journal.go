@@ -0,0 +1,184 @@
+// Package journal provides undo and redo for goods' containers. The
+// changes made through a journal are recorded with their inverse, and
+// can be grouped in transactions which are undone as one.
+package journal
+
+import "errors"
+
+// A journal has a limit on the number of groups it keeps, the groups
+// that can be undone and redone, oldest first, and the steps of the
+// open transaction, if there is one.
+//
+// Every change made outside a transaction is a group of its own.
+// Making a change forgets everything that could be redone.
+//
+// A journal is not thread-safe, and its container should only be
+// changed through it, or the inverse steps may not fit anymore.
+type Journal struct {
+	limit  int
+	undone [][]step
+	done   [][]step
+	tx     []step
+	inTx   bool
+}
+
+// A step knows how to redo and undo one change.
+type step struct {
+	redo func() error
+	undo func() error
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// newJournal creates a journal keeping at most limit groups.
+// A limit of 0 or less keeps every group.
+func newJournal(limit int) *Journal {
+	return &Journal{limit: limit}
+}
+
+// Begin starts a transaction. The changes until Commit are undone
+// and redone as one.
+//
+// e.g. j.Begin(); j.AddLast(1); j.AddLast(2); j.Commit(); j.Undo() => ()
+//
+func (J *Journal) Begin() error {
+	if J.inTx {
+		return errors.New("Transaction already started.")
+	}
+	J.inTx = true
+	J.tx = nil
+	return nil
+}
+
+// Commit ends the transaction, keeping its changes.
+func (J *Journal) Commit() error {
+	if !J.inTx {
+		return errors.New("No transaction started.")
+	}
+	J.inTx = false
+	if len(J.tx) > 0 {
+		J.push(J.tx)
+	}
+	J.tx = nil
+	return nil
+}
+
+// Rollback ends the transaction, undoing its changes. What could be
+// redone before the transaction can still be redone. If a change
+// can't be undone, the transaction stays open with its changes.
+func (J *Journal) Rollback() error {
+	if !J.inTx {
+		return errors.New("No transaction started.")
+	}
+	if err := undo(J.tx); err != nil {
+		return err
+	}
+	J.inTx = false
+	J.tx = nil
+	return nil
+}
+
+// Undo undoes the last group of changes. If a change can't be
+// undone, the group is left as it was and can be undone again.
+//
+// e.g. (1,2).AddLast(3); Undo() => (1,2)
+//
+func (J *Journal) Undo() error {
+	if J.inTx {
+		return errors.New("Transaction in progress.")
+	}
+	if len(J.done) == 0 {
+		return errors.New("Nothing to undo.")
+	}
+
+	group := J.done[len(J.done)-1]
+	if err := undo(group); err != nil {
+		return err
+	}
+	J.done = J.done[:len(J.done)-1]
+	J.undone = append(J.undone, group)
+	return nil
+}
+
+// Redo redoes the last group of changes that was undone. If a change
+// can't be redone, the group is left as it was and can be redone again.
+//
+// e.g. (1,2).AddLast(3); Undo(); Redo() => (1,2,3)
+//
+func (J *Journal) Redo() error {
+	if J.inTx {
+		return errors.New("Transaction in progress.")
+	}
+	if len(J.undone) == 0 {
+		return errors.New("Nothing to redo.")
+	}
+
+	group := J.undone[len(J.undone)-1]
+	if err := redo(group); err != nil {
+		return err
+	}
+	J.undone = J.undone[:len(J.undone)-1]
+	J.done = append(J.done, group)
+	return nil
+}
+
+// CanUndo returns true if there is a group of changes to undo.
+func (J *Journal) CanUndo() bool {
+	return !J.inTx && len(J.done) > 0
+}
+
+// CanRedo returns true if there is a group of changes to redo.
+func (J *Journal) CanRedo() bool {
+	return !J.inTx && len(J.undone) > 0
+}
+
+// record adds a change which has just been made.
+func (J *Journal) record(redo, undo func() error) {
+	s := step{redo, undo}
+	if J.inTx {
+		J.tx = append(J.tx, s)
+		return
+	}
+	J.push([]step{s})
+}
+
+// push adds a group, forgets what could be redone, and
+// forgets the oldest group if there are too many.
+func (J *Journal) push(group []step) {
+	J.undone = nil
+	J.done = append(J.done, group)
+	if J.limit > 0 && len(J.done) > J.limit {
+		J.done[0] = nil
+		J.done = J.done[1:]
+	}
+}
+
+// undo undoes the steps of a group, last first. If a step fails,
+// the steps undone before it are redone, so the group is either
+// undone as a whole or not at all.
+func undo(group []step) error {
+	for i := len(group) - 1; i >= 0; i-- {
+		if err := group[i].undo(); err != nil {
+			for _, s := range group[i+1:] {
+				s.redo()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// redo redoes the steps of a group, first first. If a step fails,
+// the steps redone before it are undone again.
+func redo(group []step) error {
+	for i, s := range group {
+		if err := s.redo(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				group[j].undo()
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/emnl/goods/linkedlist"
+	"github.com/emnl/goods/redblacktree"
+)
+
+type pair struct{ key, val int }
+
+func pairLess(a, b interface{}) bool {
+	return a.(pair).key < b.(pair).key
+}
+
+// str returns the elements of the list as a string.
+func str(list *linkedlist.LinkedList) string {
+	return fmt.Sprint(list.ToSlice())
+}
+
+// treeStr returns the elements of the tree as a string.
+func treeStr(tree *redblacktree.RedBlackTree) string {
+	res := []Elem{}
+	for x := range tree.InOrder() {
+		res = append(res, x)
+	}
+	return fmt.Sprint(res)
+}
+
+func TestListUndoRedo(t *testing.T) {
+	j := NewList(linkedlist.FromSlice([]int{1, 2, 3, 2}), 0)
+
+	j.AddFirst(0)
+	j.AddLast(4)
+	j.Set(1, 8)
+	j.Remove(2)
+	j.Reverse()
+	j.RemoveFirst()
+	j.RemoveLast()
+	j.AddAt(1, 5)
+	j.RemoveAt(2)
+
+	states := []string{
+		"[0 1 2 3 2]",
+		"[0 1 2 3 2 4]",
+		"[0 8 2 3 2 4]",
+		"[0 8 3 2 4]",
+		"[4 2 3 8 0]",
+		"[2 3 8 0]",
+		"[2 3 8]",
+		"[2 5 3 8]",
+		"[2 5 8]",
+	}
+
+	for i := len(states) - 1; i > 0; i-- {
+		if str(j.List()) != states[i] {
+			t.Fatalf("Undo should restore %v, got %v.", states[i], str(j.List()))
+		}
+		if j.Undo() != nil {
+			t.Fatalf("Undo should undo a change.")
+		}
+	}
+	j.Undo()
+	if str(j.List()) != "[1 2 3 2]" || j.CanUndo() || j.Undo() == nil {
+		t.Errorf("Undo should restore the list as it was, got %v.", str(j.List()))
+	}
+
+	for _, want := range states {
+		if j.Redo() != nil || str(j.List()) != want {
+			t.Fatalf("Redo should restore %v, got %v.", want, str(j.List()))
+		}
+	}
+	if j.CanRedo() || j.Redo() == nil {
+		t.Errorf("Redo should fail when nothing was undone.")
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	j := NewList(linkedlist.New(), 0)
+
+	if j.Set(0, 1) == nil || j.Remove(1) == nil || j.RemoveFirst() == nil || j.RemoveLast() == nil {
+		t.Errorf("Failed changes should return an error.")
+	}
+	if j.AddAt(1, 1) == nil || j.RemoveAt(0) == nil {
+		t.Errorf("Failed changes should return an error.")
+	}
+	if j.CanUndo() {
+		t.Errorf("Failed changes should not be recorded.")
+	}
+}
+
+func TestListOutOfBound(t *testing.T) {
+	j := NewList(linkedlist.FromSlice([]int{1, 2, 3}), 0)
+
+	for _, i := range []int{-1, -5, 3, 10} {
+		if j.Set(i, 0) == nil {
+			t.Errorf("Set(%d) should refuse an index out of bound.", i)
+		}
+		if j.RemoveAt(i) == nil {
+			t.Errorf("RemoveAt(%d) should refuse an index out of bound.", i)
+		}
+	}
+	if str(j.List()) != "[1 2 3]" || j.CanUndo() {
+		t.Errorf("Refused changes should leave the list and the journal alone.")
+	}
+}
+
+func TestRedoForgotten(t *testing.T) {
+	j := NewList(linkedlist.New(), 0)
+
+	j.AddLast(1)
+	j.AddLast(2)
+	j.Undo()
+	j.AddLast(3)
+
+	if j.CanRedo() || str(j.List()) != "[1 3]" {
+		t.Errorf("A new change should forget what could be redone.")
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	j := NewList(linkedlist.FromSlice([]int{1}), 0)
+
+	if j.Commit() == nil || j.Rollback() == nil {
+		t.Errorf("Commit and Rollback should fail without a transaction.")
+	}
+
+	j.Begin()
+	if j.Begin() == nil {
+		t.Errorf("Begin should fail inside a transaction.")
+	}
+	j.AddLast(2)
+	j.AddLast(3)
+	j.Reverse()
+	if j.CanUndo() || j.Undo() == nil || j.Redo() == nil {
+		t.Errorf("Undo and Redo should fail inside a transaction.")
+	}
+	j.Commit()
+	j.AddLast(4)
+
+	j.Undo()
+	if str(j.List()) != "[3 2 1]" {
+		t.Errorf("Undo should undo a single change, got %v.", str(j.List()))
+	}
+	j.Undo()
+	if str(j.List()) != "[1]" || j.CanUndo() {
+		t.Errorf("Undo should undo a whole transaction, got %v.", str(j.List()))
+	}
+	j.Redo()
+	if str(j.List()) != "[3 2 1]" {
+		t.Errorf("Redo should redo a whole transaction, got %v.", str(j.List()))
+	}
+
+	j.Begin()
+	j.RemoveFirst()
+	j.Set(0, 7)
+	j.AddFirst(0)
+	if j.Rollback() != nil || str(j.List()) != "[3 2 1]" {
+		t.Errorf("Rollback should undo the transaction, got %v.", str(j.List()))
+	}
+	if !j.CanRedo() {
+		t.Errorf("Rollback should not touch what came before the transaction.")
+	}
+
+	j.Begin()
+	j.Commit()
+	j.Undo()
+	if str(j.List()) != "[1]" {
+		t.Errorf("An empty transaction should not be recorded.")
+	}
+}
+
+func TestFailedStep(t *testing.T) {
+	j := newJournal(0)
+	applied := map[int]bool{}
+	broken := false
+	change := func(i int) {
+		applied[i] = true
+		j.record(func() error {
+			if broken && i == 1 {
+				return errors.New("Broken.")
+			}
+			applied[i] = true
+			return nil
+		}, func() error {
+			if broken && i == 1 {
+				return errors.New("Broken.")
+			}
+			applied[i] = false
+			return nil
+		})
+	}
+	count := func() int {
+		n := 0
+		for _, ok := range applied {
+			if ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	j.Begin()
+	change(0)
+	change(1)
+	change(2)
+	broken = true
+	if j.Rollback() == nil || count() != 3 || j.Commit() != nil {
+		t.Fatalf("A failed Rollback should leave the transaction open and whole.")
+	}
+
+	if j.Undo() == nil || count() != 3 || !j.CanUndo() || j.CanRedo() {
+		t.Errorf("A failed Undo should leave the group done and whole.")
+	}
+	broken = false
+	if j.Undo() != nil || count() != 0 || !j.CanRedo() {
+		t.Errorf("Undo should work once the step works again.")
+	}
+
+	broken = true
+	if j.Redo() == nil || count() != 0 || !j.CanRedo() || j.CanUndo() {
+		t.Errorf("A failed Redo should leave the group undone and whole.")
+	}
+}
+
+func TestLimit(t *testing.T) {
+	j := NewList(linkedlist.New(), 3)
+	for i := 0; i < 10; i++ {
+		j.AddLast(i)
+	}
+
+	n := 0
+	for j.Undo() == nil {
+		n++
+	}
+	if n != 3 || j.List().Size() != 7 {
+		t.Errorf("The journal should keep at most limit groups, undid %d.", n)
+	}
+}
+
+func TestTreeUndoRedo(t *testing.T) {
+	j := NewTree(redblacktree.New(pairLess), 0)
+
+	j.Add(pair{2, 0})
+	j.Add(pair{1, 0})
+	if j.Add(pair{2, 1}) == nil {
+		t.Errorf("Add should return the error of the tree on replace.")
+	}
+	j.Remove(pair{1, 0})
+	if j.Remove(pair{3, 0}) == nil {
+		t.Errorf("Remove should refuse an element that doesn't exist.")
+	}
+
+	states := []string{
+		"[{2 0}]",
+		"[{1 0} {2 0}]",
+		"[{1 0} {2 1}]",
+		"[{2 1}]",
+	}
+	for i := len(states) - 1; i > 0; i-- {
+		if treeStr(j.Tree()) != states[i] {
+			t.Fatalf("Undo should restore %v, got %v.", states[i], treeStr(j.Tree()))
+		}
+		j.Undo()
+	}
+	j.Undo()
+	if !j.Tree().Empty() {
+		t.Errorf("Undo should restore the tree as it was.")
+	}
+
+	for _, want := range states {
+		if j.Redo() != nil || treeStr(j.Tree()) != want {
+			t.Fatalf("Redo should restore %v, got %v.", want, treeStr(j.Tree()))
+		}
+	}
+}
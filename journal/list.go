@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"errors"
+
+	"github.com/emnl/goods/linkedlist"
+)
+
+// List records the changes made to a linkedlist through it.
+//
+// e.g. j := journal.NewList(mylist, 100)
+//      j.AddLast(4)
+//      j.Undo()
+//
+type List struct {
+	*Journal
+	list *linkedlist.LinkedList
+}
+
+// NewList is used as a constructor for a List journal over the given
+// linkedlist, keeping at most limit groups of changes. A limit of 0 or
+// less keeps every group.
+//
+// e.g. j := journal.NewList(mylist, 100)
+//
+func NewList(list *linkedlist.LinkedList, limit int) *List {
+	return &List{newJournal(limit), list}
+}
+
+// List returns the linkedlist of the journal.
+func (J *List) List() *linkedlist.LinkedList {
+	return J.list
+}
+
+// AddFirst adds the element first in the list.
+//
+// e.g. (1,2).AddFirst(0) => (0,1,2)
+//
+func (J *List) AddFirst(V Elem) {
+	J.list.AddFirst(V)
+	J.record(func() error {
+		J.list.AddFirst(V)
+		return nil
+	}, J.list.RemoveFirst)
+}
+
+// AddLast adds the element last in the list.
+//
+// e.g. (1,2).AddLast(3) => (1,2,3)
+//
+func (J *List) AddLast(V Elem) {
+	J.list.AddLast(V)
+	J.record(func() error {
+		J.list.AddLast(V)
+		return nil
+	}, J.list.RemoveLast)
+}
+
+// Set replaces the element at the given index.
+//
+// e.g. (1,2,3).Set(1, 8) => (1,8,3)
+//
+func (J *List) Set(i int, V Elem) error {
+	if i < 0 || i >= J.list.Size() {
+		return errors.New("Index out of bound.")
+	}
+
+	old := J.list.Get(i)
+	if err := J.list.Set(i, V); err != nil {
+		return err
+	}
+
+	J.record(func() error {
+		return J.list.Set(i, V)
+	}, func() error {
+		return J.list.Set(i, old)
+	})
+	return nil
+}
+
+// Remove deletes the first occurrence of the element.
+//
+// e.g. (1,2,1).Remove(1) => (2,1)
+//
+func (J *List) Remove(V Elem) error {
+	i := J.list.Index(V)
+	if i < 0 {
+		return errors.New("Item not found in list.")
+	}
+	return J.RemoveAt(i)
+}
+
+// RemoveFirst deletes the first element.
+//
+// e.g. (1,2,3).RemoveFirst() => (2,3)
+//
+func (J *List) RemoveFirst() error {
+	return J.RemoveAt(0)
+}
+
+// RemoveLast deletes the last element.
+//
+// e.g. (1,2,3).RemoveLast() => (1,2)
+//
+func (J *List) RemoveLast() error {
+	return J.RemoveAt(J.list.Size() - 1)
+}
+
+// RemoveAt deletes the element at the given index.
+//
+// e.g. (1,2,3).RemoveAt(1) => (1,3)
+//
+func (J *List) RemoveAt(i int) error {
+	if i < 0 || i >= J.list.Size() {
+		return errors.New("Index out of bound.")
+	}
+
+	old := J.list.Get(i)
+	if err := J.list.RemoveAt(i); err != nil {
+		return err
+	}
+
+	J.record(func() error {
+		return J.list.RemoveAt(i)
+	}, func() error {
+		return J.list.AddAt(i, old)
+	})
+	return nil
+}
+
+// AddAt adds the element at the given index.
+//
+// e.g. (1,2,3).AddAt(1, 8) => (1,8,2,3)
+//
+func (J *List) AddAt(i int, V Elem) error {
+	if err := J.list.AddAt(i, V); err != nil {
+		return err
+	}
+
+	J.record(func() error {
+		return J.list.AddAt(i, V)
+	}, func() error {
+		return J.list.RemoveAt(i)
+	})
+	return nil
+}
+
+// Reverse reverses the list.
+//
+// e.g. (1,2,3).Reverse() => (3,2,1)
+//
+func (J *List) Reverse() {
+	J.list.Reverse()
+
+	reverse := func() error {
+		J.list.Reverse()
+		return nil
+	}
+	J.record(reverse, reverse)
+}
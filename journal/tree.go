@@ -0,0 +1,81 @@
+package journal
+
+import (
+	"errors"
+
+	"github.com/emnl/goods/redblacktree"
+)
+
+// Tree records the changes made to a redblacktree through it.
+//
+// e.g. j := journal.NewTree(mytree, 100)
+//      j.Add(4)
+//      j.Undo()
+//
+type Tree struct {
+	*Journal
+	tree *redblacktree.RedBlackTree
+}
+
+// NewTree is used as a constructor for a Tree journal over the given
+// redblacktree, keeping at most limit groups of changes. A limit of 0
+// or less keeps every group.
+//
+// e.g. j := journal.NewTree(mytree, 100)
+//
+func NewTree(tree *redblacktree.RedBlackTree, limit int) *Tree {
+	return &Tree{newJournal(limit), tree}
+}
+
+// Tree returns the redblacktree of the journal.
+func (J *Tree) Tree() *redblacktree.RedBlackTree {
+	return J.tree
+}
+
+// Add inserts the element into the tree. Like the Add of the tree,
+// it replaces an equal element and returns an error; undoing it puts
+// the old element back.
+//
+// e.g. (2 (1) ()).Add(3) => (2 (1) (3))
+//
+func (J *Tree) Add(E Elem) error {
+	old := J.tree.Get(E)
+	err := J.tree.Add(E)
+
+	redo := func() error {
+		J.tree.Add(E)
+		return nil
+	}
+	if old == nil {
+		J.record(redo, func() error {
+			return J.tree.Remove(E)
+		})
+	} else {
+		J.record(redo, func() error {
+			J.tree.Add(old)
+			return nil
+		})
+	}
+	return err
+}
+
+// Remove deletes the element from the tree.
+//
+// e.g. (2 (1) (3)).Remove(2) => (3 (1) ())
+//
+func (J *Tree) Remove(E Elem) error {
+	old := J.tree.Get(E)
+	if old == nil {
+		return errors.New("Item not found in Tree.")
+	}
+	if err := J.tree.Remove(E); err != nil {
+		return err
+	}
+
+	J.record(func() error {
+		return J.tree.Remove(E)
+	}, func() error {
+		return J.tree.Add(old)
+	})
+	return nil
+}
@@ -0,0 +1,299 @@
+package linkedlist
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Errors returned to the subscribers of a Broadcast.
+var (
+	ErrClosed       = errors.New("Broadcast is closed.")
+	ErrEvicted      = errors.New("Subscriber was evicted.")
+	ErrUnsubscribed = errors.New("Subscriber is unsubscribed.")
+)
+
+// Policy decides what a Broadcast does when a subscriber falls
+// behind by its limit.
+type Policy int
+
+const (
+	// Drop skips the oldest element the subscriber has not read,
+	// which it counts in Dropped.
+	Drop Policy = iota
+
+	// Block makes Publish wait until the subscriber reads.
+	Block
+
+	// Evict unsubscribes the subscriber, whose Next then
+	// returns ErrEvicted.
+	Evict
+)
+
+// A broadcast is a chain of nodes which ends in an empty node, where
+// the next element goes. Every subscriber has its own cursor into the
+// chain, so each of them reads every element. The chain has no head:
+// the nodes every cursor has passed are left to the garbage collector.
+//
+// e.g. Publish(1), Publish(2), Publish(3):
+//      1 -> 2 -> 3 -> _ <- tail
+//      ^         ^
+//      a         b
+//
+// The wake channel is closed, and replaced, when there is something
+// new to read; the room channel when a subscriber reads or leaves.
+//
+// The broadcast is thread-safe.
+type Broadcast struct {
+	mu     sync.Mutex
+	tail   *node
+	seq    uint64
+	limit  int
+	policy Policy
+	subs   []*Subscriber
+	wake   chan struct{}
+	room   chan struct{}
+	closed bool
+}
+
+// A Subscriber reads the elements of a Broadcast published after
+// it subscribed. It has the node to read next, the number of elements
+// published before that node, and the number it has missed.
+type Subscriber struct {
+	b       *Broadcast
+	cursor  *node
+	pos     uint64
+	dropped uint64
+	err     error
+}
+
+// NewBroadcast is used as a constructor for the Broadcast struct. A
+// subscriber may fall behind by limit elements before the policy kicks
+// in. A limit of 0 or less lets it fall behind without bound.
+//
+// e.g. events := linkedlist.NewBroadcast(1000, linkedlist.Drop)
+//
+func NewBroadcast(limit int, policy Policy) *Broadcast {
+	return &Broadcast{
+		tail:   &node{},
+		limit:  limit,
+		policy: policy,
+		wake:   make(chan struct{}),
+		room:   make(chan struct{}),
+	}
+}
+
+// Publish appends an element, which every subscriber will read.
+// With the Block policy it waits until every subscriber has room.
+// It returns ErrClosed if the broadcast is closed.
+//
+// e.g. events.Publish("started")
+//
+func (B *Broadcast) Publish(V Elem) error {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	for !B.closed && B.limit > 0 {
+		slow := B.slowest()
+		if slow == nil || B.seq-slow.pos < uint64(B.limit) {
+			break
+		}
+
+		switch B.policy {
+		case Drop:
+			for _, S := range B.subs {
+				if B.seq-S.pos >= uint64(B.limit) {
+					S.cursor = S.cursor.next
+					S.pos++
+					S.dropped++
+				}
+			}
+		case Evict:
+			for _, S := range append([]*Subscriber{}, B.subs...) {
+				if B.seq-S.pos >= uint64(B.limit) {
+					B.remove(S, ErrEvicted)
+				}
+			}
+		default:
+			room := B.room
+			B.mu.Unlock()
+			<-room
+			B.mu.Lock()
+		}
+	}
+	if B.closed {
+		return ErrClosed
+	}
+
+	B.tail.Value = V
+	B.tail.next = &node{}
+	B.tail = B.tail.next
+	B.seq++
+	B.signal(&B.wake)
+	return nil
+}
+
+// Subscribe returns a new subscriber, which reads the elements
+// published from now on.
+//
+// e.g. sub := events.Subscribe()
+//
+func (B *Broadcast) Subscribe() *Subscriber {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	S := &Subscriber{b: B, cursor: B.tail, pos: B.seq}
+	if B.closed {
+		S.err = ErrClosed
+		return S
+	}
+	B.subs = append(B.subs, S)
+	return S
+}
+
+// Unsubscribe removes a subscriber, whose Next then returns
+// ErrUnsubscribed. The elements it has not read are dropped.
+//
+// e.g. events.Unsubscribe(sub)
+//
+func (B *Broadcast) Unsubscribe(S *Subscriber) error {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	if !B.remove(S, ErrUnsubscribed) {
+		return errors.New("Subscriber not found in Broadcast.")
+	}
+	return nil
+}
+
+// Subscribers returns the number of subscribers.
+//
+// e.g. (a, b).Subscribers() => 2
+//
+func (B *Broadcast) Subscribers() int {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	return len(B.subs)
+}
+
+// Close closes the broadcast. The subscribers may still read what
+// was published before, and then Next returns ErrClosed.
+func (B *Broadcast) Close() error {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	if B.closed {
+		return ErrClosed
+	}
+	B.closed = true
+	B.signal(&B.wake)
+	B.signal(&B.room)
+	return nil
+}
+
+// Next returns the next element, waiting until one is published.
+// It returns ErrClosed once the broadcast is closed and everything
+// before has been read, ErrEvicted or ErrUnsubscribed once the
+// subscriber has left, or the error of the context if the context
+// is done first.
+//
+// e.g. for { v, err := sub.Next(ctx); if err != nil { break } }
+//
+func (S *Subscriber) Next(ctx context.Context) (Elem, error) {
+	B := S.b
+	for {
+		B.mu.Lock()
+		V, ok := S.read()
+		if ok {
+			B.mu.Unlock()
+			return V, nil
+		}
+		err := S.err
+		if err == nil && B.closed {
+			err = ErrClosed
+		}
+		wake := B.wake
+		B.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+// Poll returns the next element, and true, if one has been
+// published. Otherwise it returns false without waiting.
+//
+// e.g. sub.Poll() => "started", true
+//
+func (S *Subscriber) Poll() (Elem, bool) {
+	S.b.mu.Lock()
+	defer S.b.mu.Unlock()
+
+	return S.read()
+}
+
+// Dropped returns the number of elements the subscriber missed
+// because it fell behind, with the Drop policy.
+func (S *Subscriber) Dropped() uint64 {
+	S.b.mu.Lock()
+	defer S.b.mu.Unlock()
+
+	return S.dropped
+}
+
+// read moves the cursor past the next element and returns it,
+// if there is one and the subscriber has not left.
+func (S *Subscriber) read() (Elem, bool) {
+	B := S.b
+	if S.err != nil || S.cursor == B.tail {
+		return nil, false
+	}
+
+	V := S.cursor.Value
+	S.cursor = S.cursor.next
+	S.pos++
+	if B.policy == Block {
+		B.signal(&B.room)
+	}
+	return V, true
+}
+
+// slowest returns the subscriber which has read the least,
+// or nil if there are no subscribers.
+func (B *Broadcast) slowest() *Subscriber {
+	var res *Subscriber
+	for _, S := range B.subs {
+		if res == nil || S.pos < res.pos {
+			res = S
+		}
+	}
+	return res
+}
+
+// remove removes a subscriber and tells it why. It returns
+// false if the subscriber was not subscribed.
+func (B *Broadcast) remove(S *Subscriber, err error) bool {
+	for i, s := range B.subs {
+		if s == S {
+			B.subs = append(B.subs[:i], B.subs[i+1:]...)
+			S.err, S.cursor = err, nil
+			B.signal(&B.wake)
+			B.signal(&B.room)
+			return true
+		}
+	}
+	return false
+}
+
+// signal wakes everyone waiting on the channel, and replaces it.
+func (B *Broadcast) signal(ch *chan struct{}) {
+	close(*ch)
+	*ch = make(chan struct{})
+}
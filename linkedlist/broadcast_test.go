@@ -0,0 +1,184 @@
+package linkedlist
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readAll polls every element the subscriber can read.
+func readAll(sub *Subscriber) []Elem {
+	res := []Elem{}
+	for {
+		x, ok := sub.Poll()
+		if !ok {
+			return res
+		}
+		res = append(res, x)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	b := NewBroadcast(0, Drop)
+	b.Publish(0)
+
+	a := b.Subscribe()
+	b.Publish(1)
+	c := b.Subscribe()
+	b.Publish(2)
+
+	if res := readAll(a); len(res) != 2 || res[0] != 1 || res[1] != 2 {
+		t.Errorf("A subscriber should read everything published after it subscribed, got %v.", res)
+	}
+	if res := readAll(c); len(res) != 1 || res[0] != 2 {
+		t.Errorf("Every subscriber should have its own cursor, got %v.", res)
+	}
+	if b.Subscribers() != 2 {
+		t.Errorf("Subscribe should add subscribers.")
+	}
+}
+
+func TestBroadcastUnsubscribe(t *testing.T) {
+	b := NewBroadcast(0, Drop)
+	a := b.Subscribe()
+	b.Publish(1)
+
+	if b.Unsubscribe(a) != nil || b.Unsubscribe(a) == nil {
+		t.Errorf("Unsubscribe should remove a subscriber once.")
+	}
+	if _, ok := a.Poll(); ok {
+		t.Errorf("An unsubscribed subscriber should read nothing.")
+	}
+	if _, err := a.Next(context.Background()); err != ErrUnsubscribed {
+		t.Errorf("Next should return ErrUnsubscribed, got %v.", err)
+	}
+	if b.Subscribers() != 0 {
+		t.Errorf("Unsubscribe should remove the subscriber.")
+	}
+}
+
+func TestBroadcastDrop(t *testing.T) {
+	b := NewBroadcast(3, Drop)
+	slow, fast := b.Subscribe(), b.Subscribe()
+
+	for i := 0; i < 10; i++ {
+		b.Publish(i)
+		readAll(fast)
+	}
+
+	if res := readAll(slow); len(res) != 3 || res[0] != 7 {
+		t.Errorf("Drop should keep the newest elements, got %v.", res)
+	}
+	if slow.Dropped() != 7 || fast.Dropped() != 0 {
+		t.Errorf("Dropped should count the missed elements, got %d.", slow.Dropped())
+	}
+}
+
+func TestBroadcastEvict(t *testing.T) {
+	b := NewBroadcast(3, Evict)
+	slow, fast := b.Subscribe(), b.Subscribe()
+
+	for i := 0; i < 5; i++ {
+		b.Publish(i)
+		readAll(fast)
+	}
+
+	if _, err := slow.Next(context.Background()); err != ErrEvicted {
+		t.Errorf("A slow subscriber should be evicted, got %v.", err)
+	}
+	if b.Subscribers() != 1 || len(readAll(fast)) != 0 {
+		t.Errorf("Evict should leave the other subscribers.")
+	}
+}
+
+func TestBroadcastBlock(t *testing.T) {
+	b := NewBroadcast(2, Block)
+	sub := b.Subscribe()
+	b.Publish(1)
+	b.Publish(2)
+
+	done := make(chan struct{})
+	go func() {
+		b.Publish(3)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("Publish should block on a full subscriber.")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if x, _ := sub.Poll(); x != 1 {
+		t.Errorf("Poll should read the oldest element.")
+	}
+	<-done
+	if res := readAll(sub); len(res) != 2 || res[1] != 3 {
+		t.Errorf("Publish should go on once there is room, got %v.", res)
+	}
+}
+
+func TestBroadcastClose(t *testing.T) {
+	b := NewBroadcast(1, Block)
+	sub := b.Subscribe()
+	b.Publish(1)
+
+	errs := make(chan error)
+	go func() { errs <- b.Publish(2) }()
+	time.Sleep(10 * time.Millisecond)
+	b.Close()
+
+	if <-errs != ErrClosed || b.Publish(3) != ErrClosed || b.Close() != ErrClosed {
+		t.Errorf("A closed broadcast should return ErrClosed.")
+	}
+	if x, err := sub.Next(context.Background()); x != 1 || err != nil {
+		t.Errorf("Next should read what was published before Close.")
+	}
+	if _, err := sub.Next(context.Background()); err != ErrClosed {
+		t.Errorf("Next should return ErrClosed after the last element, got %v.", err)
+	}
+	if _, err := b.Subscribe().Next(context.Background()); err != ErrClosed {
+		t.Errorf("Subscribe on a closed broadcast should return a closed subscriber.")
+	}
+}
+
+func TestBroadcastNext(t *testing.T) {
+	b := NewBroadcast(0, Drop)
+	sub := b.Subscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := sub.Next(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Next should return the error of the context, got %v.", err)
+	}
+
+	var wg sync.WaitGroup
+	subs := []*Subscriber{b.Subscribe(), b.Subscribe(), b.Subscribe()}
+	sums := make([]int, len(subs))
+	for i, s := range subs {
+		wg.Add(1)
+		go func(i int, s *Subscriber) {
+			defer wg.Done()
+			for {
+				x, err := s.Next(context.Background())
+				if err != nil {
+					return
+				}
+				sums[i] += x.(int)
+			}
+		}(i, s)
+	}
+
+	for i := 1; i <= 100; i++ {
+		b.Publish(i)
+	}
+	b.Close()
+	wg.Wait()
+
+	for _, sum := range sums {
+		if sum != 5050 {
+			t.Errorf("Every subscriber should read every element, got %d.", sum)
+		}
+	}
+}
@@ -4,8 +4,6 @@
 package linkedlist
 
 import (
-	"bytes"
-	"encoding/gob"
 	"errors"
 	"reflect"
 	"sync"
@@ -21,10 +19,13 @@ import (
 //      last -> 4
 //
 type LinkedList struct {
-	size  int
-	first *node
-	last  *node
-	mu    sync.RWMutex
+	size   int
+	first  *node
+	last   *node
+	mu     sync.RWMutex
+	subs   []subscriber
+	nextID int
+	seq    uint64
 }
 
 // The linkedlist's chain is made up of nodes with an element,
@@ -80,7 +81,11 @@ func (L *LinkedList) Empty() bool {
 // e.g. (1,2,3).AddFirst(0) => (0,1,2,3)
 //
 func (L *LinkedList) AddFirst(V Elem) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
 	n := node{V, nil, nil}
 
 	if L.size == 0 {
@@ -92,7 +97,7 @@ func (L *LinkedList) AddFirst(V Elem) {
 
 	L.first = &n
 	L.size += 1
-	L.mu.Unlock()
+	L.record(&p, OpAdd, func() int { return 0 }, V)
 }
 
 // AddLast adds a node at the end of the list with
@@ -101,7 +106,11 @@ func (L *LinkedList) AddFirst(V Elem) {
 // e.g. (1,2,3).AddLast(4) => (1,2,3,4)
 //
 func (L *LinkedList) AddLast(V Elem) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
 	n := node{V, nil, L.last}
 
 	if L.size == 0 {
@@ -112,7 +121,7 @@ func (L *LinkedList) AddLast(V Elem) {
 
 	L.last = &n
 	L.size += 1
-	L.mu.Unlock()
+	L.record(&p, OpAdd, func() int { return L.size - 1 }, V)
 }
 
 // Contains returns true if the list has at least one
@@ -137,9 +146,12 @@ func (L *LinkedList) Contains(V Elem) bool {
 // e.g. (1,2,1).Index(1) => 0
 //
 func (L *LinkedList) Index(V Elem) int {
+	L.mu.RLock()
+	defer L.mu.RUnlock()
+
 	i := 0
-	for n := range L.iter() {
-		if n == V {
+	for n := L.first; n != nil; n = n.next {
+		if n.Value == V {
 			return i
 		}
 		i++
@@ -167,16 +179,66 @@ func (L *LinkedList) Get(i int) Elem {
 // e.g. (1,2,3).Set(1, 8) => (1,8,3)
 //
 func (L *LinkedList) Set(i int, V Elem) error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	node, err := L.getNode(i)
 	if err == nil {
 		node.Value = V
+		L.record(&p, OpSet, func() int { return i }, V)
 	}
 	return err
 }
 
+// AddAt adds a node with the given element at the given
+// index. The index may be the size of the list, to add last.
+//
+// e.g. (1,2,3).AddAt(1, 8) => (1,8,2,3)
+//
+func (L *LinkedList) AddAt(i int, V Elem) error {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	if i < 0 || i > L.size {
+		return errors.New("Index out of bound.")
+	}
+
+	var prev *node
+	if i > 0 {
+		prev, _ = L.getNode(i - 1)
+	}
+	L.insertAfter(prev, V)
+	L.record(&p, OpAdd, func() int { return i }, V)
+	return nil
+}
+
+// RemoveAt deletes the node at the given index.
+//
+// e.g. (1,2,3).RemoveAt(1) => (1,3)
+//
+func (L *LinkedList) RemoveAt(i int) error {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	if i < 0 {
+		return errors.New("Index out of bound.")
+	}
+	node, err := L.getNode(i)
+	if err != nil {
+		return err
+	}
+
+	L.removeNode(node)
+	L.record(&p, OpRemove, func() int { return i }, node.Value)
+	return nil
+}
+
 // First returns the first nodes' element.
 //
 // e.g. (1,2,3).First() => 1
@@ -212,31 +274,62 @@ func (L *LinkedList) Last() Elem {
 // e.g. (1,2,3).RemoveFirst() => (2,3)
 //
 func (L *LinkedList) RemoveFirst() error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	if L.size == 0 {
 		return errors.New("List is empty.")
 	}
 
+	V := L.first.Value
 	L.removeNode(L.first)
+	L.record(&p, OpRemove, func() int { return 0 }, V)
 	return nil
 }
 
+// TakeFirst deletes the first node in the list and
+// returns its element, under a single lock. It returns
+// false if the list is empty.
+//
+// e.g. (1,2,3).TakeFirst() => 1, true
+//       --^-- .TakeFirst() => 2, true
+//
+func (L *LinkedList) TakeFirst() (Elem, bool) {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	if L.size == 0 {
+		return nil, false
+	}
+
+	V := L.first.Value
+	L.removeNode(L.first)
+	L.record(&p, OpRemove, func() int { return 0 }, V)
+	return V, true
+}
+
 // RemoveLast deletes the last node in the
 // list.
 //
 // e.g. (1,2,3).RemoveLast() => (1,2)
 //
 func (L *LinkedList) RemoveLast() error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	if L.size == 0 {
 		return errors.New("List is empty.")
 	}
 
+	V := L.last.Value
 	L.removeNode(L.last)
+	L.record(&p, OpRemove, func() int { return L.size }, V)
 	return nil
 }
 
@@ -246,7 +339,9 @@ func (L *LinkedList) RemoveLast() error {
 // e.g. (1,2,1).Remove(1) => (2,1)
 //
 func (L *LinkedList) Remove(V Elem) error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	res := L.slowGet(V)
@@ -254,6 +349,7 @@ func (L *LinkedList) Remove(V Elem) error {
 		return errors.New("Item not found in list.")
 	}
 
+	L.record(&p, OpRemove, func() int { return L.indexOf(res) }, res.Value)
 	L.removeNode(res)
 	return nil
 }
@@ -265,7 +361,9 @@ func (L *LinkedList) Remove(V Elem) error {
 // e.g. (1,2,1).FastRemove(1) => (1,2)
 //
 func (L *LinkedList) FastRemove(V Elem) error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	res := L.fastGet(V)
@@ -273,6 +371,7 @@ func (L *LinkedList) FastRemove(V Elem) error {
 		return errors.New("Item not found in list.")
 	}
 
+	L.record(&p, OpRemove, func() int { return L.indexOf(res) }, res.Value)
 	L.removeNode(res)
 	return nil
 }
@@ -282,14 +381,21 @@ func (L *LinkedList) FastRemove(V Elem) error {
 // e.g. (1,2,1).RemoveAll(1) => (2)
 //
 func (L *LinkedList) RemoveAll(V Elem) error {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	s := L.size
 
+	i := 0
 	for n := L.first; n != nil; n = n.next {
 		if n.Value == V {
 			L.removeNode(n)
+			index := i
+			L.record(&p, OpRemove, func() int { return index }, n.Value)
+		} else {
+			i++
 		}
 	}
 
@@ -345,16 +451,21 @@ func FromSlice(slc interface{}) *LinkedList {
 // e.g. (1,2,3).Conc((4,5,6)) => (1,2,3,4,5,6)
 //
 func (L *LinkedList) Conc(other *LinkedList) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
+	if other.size == 0 {
+		return
+	}
+	L.reset(&p)
+
 	if L.size == 0 {
 		L.first = other.first
 		L.last = other.last
 		L.size = other.size
 		return
-	} else if other.size == 0 {
-		return
 	}
 
 	L.last.next = other.first
@@ -401,14 +512,20 @@ func (L *LinkedList) Reduce(f func(interface{}, interface{}) interface{}) interf
 // e.g. (1,2,3).Filter(>= 2) => (2,3)
 //
 func (L *LinkedList) Filter(f func(interface{}) bool) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
+	size := L.size
 	for n := L.first; n != nil; n = n.next {
 		if !f(n.Value) {
 			L.removeNode(n)
 		}
 	}
+	if L.size != size {
+		L.reset(&p)
+	}
 }
 
 // ParFilter filters the list in parallel with regards to an input function.
@@ -416,24 +533,40 @@ func (L *LinkedList) Filter(f func(interface{}) bool) {
 // e.g. (1,2,3).Filter(>= 2) => (2,3)
 //
 func (L *LinkedList) ParFilter(f func(interface{}) bool) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
+	size := L.size
 	c := make(chan bool, L.size)
+	keep := make([]bool, L.size)
 
+	/* The goroutines only decide, the nodes are unlinked below */
+	i := 0
 	for n := L.first; n != nil; n = n.next {
-		go func(n *node) {
-			if !f(n.Value) {
-				L.removeNode(n)
-			}
+		go func(i int, n *node) {
+			keep[i] = f(n.Value)
 			c <- true
-		}(n)
+		}(i, n)
+		i++
 	}
 
 	/* drain the channel */
-	for i := 0; i < L.size; i++ {
+	for i := 0; i < size; i++ {
 		<-c
 	}
+
+	i = 0
+	for n := L.first; n != nil; n = n.next {
+		if !keep[i] {
+			L.removeNode(n)
+		}
+		i++
+	}
+	if L.size != size {
+		L.reset(&p)
+	}
 }
 
 // Map performs a function on every element in the list.
@@ -441,12 +574,17 @@ func (L *LinkedList) ParFilter(f func(interface{}) bool) {
 // e.g. (1,2,3).Map(f) => (f(1),f(2),f(3))
 //
 func (L *LinkedList) Map(f func(interface{}) interface{}) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	for n := L.first; n != nil; n = n.next {
 		n.Value = f(n.Value)
 	}
+	if L.size > 0 {
+		L.reset(&p)
+	}
 }
 
 // ParMap performs a function on every element in the list, in parallel.
@@ -454,7 +592,9 @@ func (L *LinkedList) Map(f func(interface{}) interface{}) {
 // e.g. (1,2,3).ParMap(f) => (f(1),f(2),f(3))
 //
 func (L *LinkedList) ParMap(f func(interface{}) interface{}) {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	c := make(chan bool, L.size)
@@ -470,6 +610,9 @@ func (L *LinkedList) ParMap(f func(interface{}) interface{}) {
 	for i := 0; i < L.size; i++ {
 		<-c
 	}
+	if L.size > 0 {
+		L.reset(&p)
+	}
 }
 
 // Reverse reverses the list.
@@ -477,12 +620,15 @@ func (L *LinkedList) ParMap(f func(interface{}) interface{}) {
 // e.g. (1,2,3).Reverse() => (3,2,1)
 //
 func (L *LinkedList) Reverse() {
+	var p pending
 	L.mu.Lock()
+	defer p.send()
 	defer L.mu.Unlock()
 
 	if L.size == 0 || L.size == 1 {
 		return
 	}
+	L.reset(&p)
 
 	start := L.first
 
@@ -504,26 +650,22 @@ func (L *LinkedList) Reverse() {
 
 }
 
-// Serialize WIP TODO
+// Serialize returns the list encoded by MarshalBinary(),
+// or nil if an element can't be encoded.
 func (L *LinkedList) Serialize() []byte {
-	L.mu.RLock()
-	defer L.mu.RUnlock()
-
-	m := new(bytes.Buffer)
-	gob.NewEncoder(m).Encode(L.ToSlice())
-
-	return m.Bytes()
+	data, err := L.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return data
 }
 
-// Deserialize WIP TODO
+// Deserialize creates a linkedlist from the output of Serialize().
+// Data that can't be decoded gives an empty list.
 func Deserialize(bt []byte) *LinkedList {
-	p := bytes.NewBuffer(bt)
-	dec := gob.NewDecoder(p)
-
-	var slc []Elem
-	dec.Decode(&slc)
-
-	return FromSlice(slc)
+	newl := New()
+	newl.UnmarshalBinary(bt)
+	return newl
 }
 
 // iter is used internally and is not locked.
@@ -1,6 +1,8 @@
 package linkedlist
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -83,6 +85,27 @@ func TestIndex(t *testing.T) {
 	}
 }
 
+func TestIndexConcurrent(t *testing.T) {
+	list := FromSlice([]int{1, 2, 3})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 4; i < 1000; i++ {
+			list.AddLast(i)
+		}
+	}()
+
+	/* An early match must not leave a walk of the list running */
+	for i := 0; i < 1000; i++ {
+		if list.Index(2) != 1 {
+			t.Fatalf("Index should find the item while the list grows.")
+		}
+	}
+	wg.Wait()
+}
+
 func TestGet(t *testing.T) {
 	list := New()
 
@@ -111,6 +134,34 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestAddAt(t *testing.T) {
+	list := FromSlice([]int{1, 2, 3})
+
+	if list.AddAt(1, 8) != nil || list.AddAt(4, 9) != nil || list.AddAt(0, 0) != nil {
+		t.Errorf("AddAt should accept an index within the list.")
+	}
+	if list.AddAt(-1, 0) == nil || list.AddAt(7, 0) == nil {
+		t.Errorf("AddAt should refuse an index out of bound.")
+	}
+	if fmt.Sprint(list.ToSlice()) != "[0 1 8 2 3 9]" || list.Last() != 9 {
+		t.Errorf("AddAt should add the item at the given index.")
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	list := FromSlice([]int{1, 2, 3})
+
+	if list.RemoveAt(-1) == nil || list.RemoveAt(3) == nil {
+		t.Errorf("RemoveAt should refuse an index out of bound.")
+	}
+	if list.RemoveAt(1) != nil || list.RemoveAt(1) != nil {
+		t.Errorf("RemoveAt should accept an index within the list.")
+	}
+	if list.Size() != 1 || list.First() != 1 || list.Last() != 1 {
+		t.Errorf("RemoveAt should remove the item at the given index.")
+	}
+}
+
 func TestFirst(t *testing.T) {
 	list := New()
 
@@ -153,6 +204,21 @@ func TestRemoveFirst(t *testing.T) {
 	}
 }
 
+func TestTakeFirst(t *testing.T) {
+	list := New()
+
+	list.AddLast(5)
+	list.AddLast(10)
+
+	if x, ok := list.TakeFirst(); x != 5 || !ok || list.Size() != 1 {
+		t.Errorf("TakeFirst should remove and return the first element in the list.")
+	}
+	list.TakeFirst()
+	if x, ok := list.TakeFirst(); x != nil || ok {
+		t.Errorf("TakeFirst should fail on an empty list.")
+	}
+}
+
 func TestRemoveLast(t *testing.T) {
 	list := New()
 
@@ -342,8 +408,21 @@ func TestReverse(t *testing.T) {
 }
 
 func TestSerialize(t *testing.T) {
-	// TODO
+	list := FromSlice([]int{1, 2, 3})
+
+	if len(list.Serialize()) == 0 {
+		t.Errorf("Serialize should encode the list.")
+	}
 }
+
 func TestDeserialize(t *testing.T) {
-	// TODO
+	list := Deserialize(FromSlice([]int{1, 2, 3}).Serialize())
+
+	if list.Size() != 3 || list.First() != 1 || list.Last() != 3 {
+		t.Errorf("Deserialize should restore the list.")
+	}
+
+	if !Deserialize([]byte("nonsense")).Empty() {
+		t.Errorf("Deserialize should return an empty list on bad data.")
+	}
 }
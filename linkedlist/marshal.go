@@ -0,0 +1,119 @@
+package linkedlist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the list as a JSON array of its elements,
+// front first. It implements json.Marshaler.
+//
+// e.g. (1,2,3) => [1,2,3]
+//
+func (L *LinkedList) MarshalJSON() ([]byte, error) {
+	L.mu.RLock()
+	defer L.mu.RUnlock()
+
+	return json.Marshal(L.slice())
+}
+
+// UnmarshalJSON replaces the elements of the list with the ones in
+// a JSON array. The elements are decoded like any interface{} value,
+// so numbers become float64. It implements json.Unmarshaler.
+//
+// e.g. [1,2,3] => (1.0,2.0,3.0)
+//
+func (L *LinkedList) UnmarshalJSON(data []byte) error {
+	var slc []Elem
+	if err := json.Unmarshal(data, &slc); err != nil {
+		return fmt.Errorf("Corrupt list data: %v", err)
+	}
+
+	L.replace(slc)
+	return nil
+}
+
+// MarshalBinary encodes the elements of the list, front first, with
+// encoding/gob. Elements of user defined types must be registered with
+// gob.Register. It implements encoding.BinaryMarshaler.
+func (L *LinkedList) MarshalBinary() ([]byte, error) {
+	L.mu.RLock()
+	defer L.mu.RUnlock()
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(L.slice()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the elements of the list with the ones
+// encoded by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (L *LinkedList) UnmarshalBinary(data []byte) error {
+	var slc []Elem
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&slc); err != nil {
+		return fmt.Errorf("Corrupt list data: %v", err)
+	}
+
+	L.replace(slc)
+	return nil
+}
+
+// GobEncode is an alias for MarshalBinary(). It implements gob.GobEncoder.
+func (L *LinkedList) GobEncode() ([]byte, error) {
+	return L.MarshalBinary()
+}
+
+// GobDecode is an alias for UnmarshalBinary(). It implements gob.GobDecoder.
+func (L *LinkedList) GobDecode(data []byte) error {
+	return L.UnmarshalBinary(data)
+}
+
+// MarshalText encodes the list as the text of a JSON array, front
+// first. It implements encoding.TextMarshaler, which is used for
+// instance by encoding/xml and by flag values.
+//
+// e.g. (1,2,3) => [1,2,3]
+//
+func (L *LinkedList) MarshalText() ([]byte, error) {
+	return L.MarshalJSON()
+}
+
+// UnmarshalText replaces the elements of the list with the ones in
+// the output of MarshalText. It implements encoding.TextUnmarshaler.
+func (L *LinkedList) UnmarshalText(text []byte) error {
+	return L.UnmarshalJSON(text)
+}
+
+// slice is used internally and is not locked.
+func (L *LinkedList) slice() []Elem {
+	res := make([]Elem, 0, L.size)
+	for n := L.first; n != nil; n = n.next {
+		res = append(res, n.Value)
+	}
+	return res
+}
+
+// replace swaps the elements of the list for the given ones.
+func (L *LinkedList) replace(slc []Elem) {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	L.first, L.last, L.size = nil, nil, 0
+	L.reset(&p)
+
+	for _, V := range slc {
+		n := &node{V, nil, L.last}
+		if L.size == 0 {
+			L.first = n
+		} else {
+			L.last.next = n
+		}
+		L.last = n
+		L.size += 1
+	}
+}
@@ -0,0 +1,92 @@
+package linkedlist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	payload := struct {
+		Items *LinkedList `json:"items"`
+	}{FromSlice([]int{1, 2, 3})}
+
+	data, err := json.Marshal(payload)
+	if err != nil || string(data) != `{"items":[1,2,3]}` {
+		t.Errorf("MarshalJSON should write the elements front first, got %s.", data)
+	}
+
+	list := FromSlice([]int{9})
+	if err := json.Unmarshal([]byte(`["a","b"]`), list); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if list.Size() != 2 || list.First() != "a" || list.Last() != "b" {
+		t.Errorf("UnmarshalJSON should replace the elements.")
+	}
+
+	if json.Unmarshal([]byte(`{}`), list) == nil {
+		t.Errorf("UnmarshalJSON should refuse anything but an array.")
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	type payload struct {
+		Items *LinkedList `xml:"items"`
+	}
+
+	data, err := xml.Marshal(payload{FromSlice([]string{"a", "b"})})
+	if err != nil || string(data) != `<payload><items>[&#34;a&#34;,&#34;b&#34;]</items></payload>` {
+		t.Errorf("MarshalText should write the elements front first, got %s.", data)
+	}
+
+	res := payload{New()}
+	if err := xml.Unmarshal(data, &res); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if res.Items.Size() != 2 || res.Items.First() != "a" || res.Items.Last() != "b" {
+		t.Errorf("UnmarshalText should restore the elements.")
+	}
+
+	if New().UnmarshalText([]byte("1,2")) == nil {
+		t.Errorf("UnmarshalText should refuse anything but an array.")
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data, err := FromSlice([]int{1, 2, 3}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	list := New()
+	if err := list.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if list.Size() != 3 || list.Get(0) != 1 || list.Get(1) != 2 || list.Get(2) != 3 {
+		t.Errorf("UnmarshalBinary should restore the elements in order.")
+	}
+
+	if list.UnmarshalBinary(data[:len(data)-2]) == nil {
+		t.Errorf("UnmarshalBinary should refuse truncated data.")
+	}
+}
+
+func TestGob(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(FromSlice([]string{"a", "b"})); err != nil {
+		t.Fatalf("GobEncode failed: %v", err)
+	}
+
+	list := New()
+	if err := gob.NewDecoder(buf).Decode(list); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+
+	if list.Size() != 2 || list.First() != "a" || list.Last() != "b" {
+		t.Errorf("GobDecode should restore the elements in order.")
+	}
+}
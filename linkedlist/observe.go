@@ -0,0 +1,141 @@
+package linkedlist
+
+// Op is the kind of change an Event reports.
+type Op int
+
+const (
+	OpAdd    Op = iota // an element was added at Index
+	OpRemove           // the element at Index was removed
+	OpSet              // the element at Index was replaced
+	OpClear            // every element was removed
+	OpReset            // the list was changed as a whole
+)
+
+// String returns the name of the operation.
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "Add"
+	case OpRemove:
+		return "Remove"
+	case OpSet:
+		return "Set"
+	case OpClear:
+		return "Clear"
+	case OpReset:
+		return "Reset"
+	}
+	return "Unknown"
+}
+
+// Event reports a change to the list. Index is the position of the
+// change when it happened, or -1 for OpClear and OpReset. Value is the
+// added, removed or new element. Seq grows by one with every change, so
+// a subscriber can tell the order of the events.
+//
+// AddFirst, AddLast, AddAt, Set, the Remove methods, Clear and the Insert
+// of a SortedList send an event per element. Methods which change many
+// elements at once, such as Conc, Filter, Map, Reverse, Sort, Unique,
+// Merge and the Unmarshal methods, send a single OpReset instead, with
+// a nil Value; a subscriber which mirrors the list must then read it
+// again, e.g. with ToSlice. Those which left the list as it was send
+// nothing, except Map and Reverse, which can't compare the elements.
+type Event struct {
+	Op    Op
+	Index int
+	Value Elem
+	Seq   uint64
+}
+
+// subscriber is a function registered by Subscribe.
+type subscriber struct {
+	id int
+	f  func(Event)
+}
+
+// pending holds the events of a change until the lock is released.
+type pending struct {
+	subs   []subscriber
+	events []Event
+}
+
+// Subscribe registers a function which is called with every change
+// to the list, and returns a function which stops the calls. The
+// function is called after the list is unlocked, so it may use the
+// list, but calls from different goroutines may overlap.
+//
+// e.g. stop := mylist.Subscribe(func(e linkedlist.Event) { ... })
+//      defer stop()
+//
+func (L *LinkedList) Subscribe(f func(Event)) func() {
+	L.mu.Lock()
+	defer L.mu.Unlock()
+
+	L.nextID++
+	id := L.nextID
+	L.subs = append(L.subs, subscriber{id, f})
+
+	return func() {
+		L.mu.Lock()
+		defer L.mu.Unlock()
+
+		for i, s := range L.subs {
+			if s.id == id {
+				L.subs = append(L.subs[:i], L.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Clear removes every element from the list.
+//
+// e.g. (1,2,3).Clear() => ()
+//
+func (L *LinkedList) Clear() {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	L.first, L.last, L.size = nil, nil, 0
+	L.record(&p, OpClear, func() int { return -1 }, nil)
+}
+
+// record counts a change, and adds its event to p if anyone
+// listens. The index is only worked out if it is needed.
+// The list must be locked.
+func (L *LinkedList) record(p *pending, op Op, index func() int, V Elem) {
+	L.seq++
+	if len(L.subs) == 0 {
+		return
+	}
+	if p.subs == nil {
+		p.subs = append([]subscriber{}, L.subs...)
+	}
+	p.events = append(p.events, Event{op, index(), V, L.seq})
+}
+
+// reset records a change to the list as a whole.
+// The list must be locked.
+func (L *LinkedList) reset(p *pending) {
+	L.record(p, OpReset, func() int { return -1 }, nil)
+}
+
+// send calls the subscribers with the events.
+func (p *pending) send() {
+	for _, e := range p.events {
+		for _, s := range p.subs {
+			s.f(e)
+		}
+	}
+}
+
+// indexOf returns the position of the node in the list.
+func (L *LinkedList) indexOf(N *node) int {
+	i := 0
+	for n := L.first; n != N; n = n.next {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,130 @@
+package linkedlist
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// record subscribes to the list and returns the events it gets.
+func record(list *LinkedList) (*[]Event, func()) {
+	events := []Event{}
+	var mu sync.Mutex
+	stop := list.Subscribe(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+	return &events, stop
+}
+
+func TestSubscribe(t *testing.T) {
+	list := New()
+	events, stop := record(list)
+
+	list.AddLast(2)
+	list.AddFirst(1)
+	list.AddLast(3)
+	list.Set(1, 5)
+	list.Remove(5)
+	list.RemoveLast()
+	list.RemoveFirst()
+	list.Remove(7)
+	list.Clear()
+
+	want := "[{Add 0 2 1} {Add 0 1 2} {Add 2 3 3} {Set 1 5 4} {Remove 1 5 5} " +
+		"{Remove 1 3 6} {Remove 0 1 7} {Clear -1 <nil> 8}]"
+	if got := fmt.Sprint(*events); got != want {
+		t.Errorf("Subscribe got %v, want %v.", got, want)
+	}
+
+	stop()
+	list.AddLast(1)
+	if len(*events) != 8 {
+		t.Errorf("No events should be sent after unsubscribing.")
+	}
+}
+
+func TestSubscribeRemoveAll(t *testing.T) {
+	list := FromSlice([]int{1, 2, 1, 1, 3})
+	events, _ := record(list)
+
+	list.RemoveAll(1)
+	if got := fmt.Sprint(*events); got != "[{Remove 0 1 6} {Remove 1 1 7} {Remove 1 1 8}]" {
+		t.Errorf("RemoveAll should send an event for every removal, got %v.", got)
+	}
+}
+
+func TestSubscribeInsert(t *testing.T) {
+	list := NewSorted(intLess)
+	list.Insert(1)
+	list.Insert(3)
+	events, _ := record(&list.LinkedList)
+
+	list.Insert(2)
+	list.Insert(0)
+	list.Insert(4)
+	if got := fmt.Sprint(*events); got != "[{Add 1 2 3} {Add 0 0 4} {Add 4 4 5}]" {
+		t.Errorf("Insert should send its position, got %v.", got)
+	}
+}
+
+func TestSubscribeReentrant(t *testing.T) {
+	list := New()
+	sizes := []int{}
+	list.Subscribe(func(e Event) {
+		sizes = append(sizes, list.Size())
+	})
+
+	list.AddLast(1)
+	list.AddLast(2)
+	if fmt.Sprint(sizes) != "[1 2]" {
+		t.Errorf("A subscriber should be able to use the list.")
+	}
+}
+
+func TestSubscribeReset(t *testing.T) {
+	list := FromSlice([]int{3, 1, 2, 2})
+	events, _ := record(list)
+	intEq := func(a, b interface{}) bool { return a == b }
+
+	changes := []func(){
+		func() { list.Reverse() },
+		func() { list.Sort(intLess) },
+		func() { list.Unique(intEq) },
+		func() { list.Filter(func(x interface{}) bool { return x != 3 }) },
+		func() { list.ParFilter(func(x interface{}) bool { return x != 2 }) },
+		func() { list.Map(func(x interface{}) interface{} { return x.(int) * 10 }) },
+		func() { list.ParMap(func(x interface{}) interface{} { return x.(int) + 1 }) },
+		func() { list.Conc(FromSlice([]int{7, 8})) },
+		func() { list.UnmarshalJSON([]byte("[4,5]")) },
+		func() { list.UnmarshalBinary(FromSlice([]int{6, 7, 9}).Serialize()) },
+	}
+	for i, change := range changes {
+		change()
+		if len(*events) != i+1 || (*events)[i].Op != OpReset || (*events)[i].Index != -1 {
+			t.Fatalf("Change %d should send an OpReset, got %v.", i, *events)
+		}
+	}
+
+	/* Changes which leave the list as it was send nothing */
+	list.Sort(intLess)
+	list.Unique(intEq)
+	list.Filter(func(x interface{}) bool { return true })
+	list.Conc(New())
+	if len(*events) != len(changes) {
+		t.Errorf("A change that changes nothing should send no event, got %v.", *events)
+	}
+
+	sorted := NewSorted(intLess)
+	events, _ = record(&sorted.LinkedList)
+	other := NewSorted(intLess)
+	for _, x := range []int{1, 1, 2} {
+		other.Insert(x)
+	}
+	sorted.Merge(other)
+	sorted.Dedup()
+	if fmt.Sprint(*events) != "[{Reset -1 <nil> 1} {Reset -1 <nil> 2}]" {
+		t.Errorf("Merge and Dedup should send an OpReset, got %v.", *events)
+	}
+}
@@ -0,0 +1,257 @@
+package linkedlist
+
+// LessFunc is used as a user function to compare elements in the list.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// SortedList is a linkedlist which is kept sorted by a user defined
+// function. Equal elements keep the order they were inserted in.
+//
+// The methods of LinkedList which place elements at a given position,
+// such as AddFirst, AddLast, Set, Conc, Map and Reverse, don't keep
+// the order. Use Insert and Merge to add elements instead.
+type SortedList struct {
+	LinkedList
+	less LessFunc
+}
+
+// NewSorted is used as a constructor for the SortedList
+// struct.
+//
+// e.g. mylist := linkedlist.NewSorted(intLess)
+//
+func NewSorted(lf LessFunc) *SortedList {
+	return &SortedList{less: lf}
+}
+
+// Insert adds the element at its sorted position, after
+// any equal elements. It searches from the end of the list,
+// so adding elements in order takes O(1) each.
+//
+// e.g. (1,3,4).Insert(2) => (1,2,3,4)
+//
+func (S *SortedList) Insert(V Elem) {
+	var p pending
+	S.mu.Lock()
+	defer p.send()
+	defer S.mu.Unlock()
+
+	/* Find the last node that is not bigger than V */
+	prev, after := S.last, 0
+	for prev != nil && S.less(V, prev.Value) {
+		prev = prev.prev
+		after++
+	}
+	S.insertAfter(prev, V)
+	S.record(&p, OpAdd, func() int { return S.size - 1 - after }, V)
+}
+
+// Merge adds every element of the other list at its sorted position,
+// and leaves the other list untouched. Both lists must be sorted by the
+// same LessFunc. Equal elements of this list come first. O(n+m)
+//
+// e.g. (1,3,5).Merge((2,3,4)) => (1,2,3,3,4,5)
+//
+func (S *SortedList) Merge(other *SortedList) {
+	values := other.ToSlice()
+
+	var p pending
+	S.mu.Lock()
+	defer p.send()
+	defer S.mu.Unlock()
+
+	if len(values) == 0 {
+		return
+	}
+	S.reset(&p)
+
+	n := S.first
+	var prev *node
+	for _, V := range values {
+		for n != nil && !S.less(V, n.Value) {
+			prev, n = n, n.next
+		}
+		S.insertAfter(prev, V)
+		if prev == nil {
+			prev = S.first
+		} else {
+			prev = prev.next
+		}
+	}
+}
+
+// Dedup keeps the first element of every run of equal elements
+// and removes the rest.
+//
+// e.g. (1,2,2,3,3,3).Dedup() => (1,2,3)
+//
+func (S *SortedList) Dedup() {
+	S.Unique(func(a, b interface{}) bool {
+		return !S.less(a, b)
+	})
+}
+
+// Sort sorts the list by the given function. It relinks the nodes in
+// place, using O(1) extra space, and is stable, so equal elements keep
+// their order. O(n log n)
+//
+// e.g. (3,1,2).Sort(intLess) => (1,2,3)
+//
+func (L *LinkedList) Sort(less LessFunc) {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	if L.size < 2 || L.sorted(less) {
+		return
+	}
+	L.reset(&p)
+
+	L.first = mergeSort(L.first, L.size, less)
+
+	/* Only the next pointers were kept, fix the rest */
+	var prev *node
+	for n := L.first; n != nil; n = n.next {
+		n.prev = prev
+		prev = n
+	}
+	L.last = prev
+}
+
+// SortStable is an alias for Sort(), which is always stable.
+func (L *LinkedList) SortStable(less LessFunc) {
+	L.Sort(less)
+}
+
+// IsSorted returns true if no element is less than the one
+// before it.
+//
+// e.g. (1,2,2,3).IsSorted(intLess) => true
+//
+func (L *LinkedList) IsSorted(less LessFunc) bool {
+	L.mu.RLock()
+	defer L.mu.RUnlock()
+
+	return L.sorted(less)
+}
+
+// sorted is IsSorted for callers which hold the lock.
+func (L *LinkedList) sorted(less LessFunc) bool {
+	for n := L.first; n != nil && n.next != nil; n = n.next {
+		if less(n.next.Value, n.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Unique keeps the first element of every run of elements that
+// are equal by the given function, and removes the rest. After a
+// Sort, it removes every duplicate.
+//
+// e.g. (1,1,2,1).Unique(intEq) => (1,2,1)
+//
+func (L *LinkedList) Unique(eq func(a, b interface{}) bool) {
+	var p pending
+	L.mu.Lock()
+	defer p.send()
+	defer L.mu.Unlock()
+
+	size := L.size
+	for n := L.first; n != nil && n.next != nil; {
+		if eq(n.Value, n.next.Value) {
+			L.removeNode(n.next)
+			continue
+		}
+		n = n.next
+	}
+	if L.size != size {
+		L.reset(&p)
+	}
+}
+
+// insertAfter inserts a node with the element after the given
+// node, or first if the node is nil.
+func (L *LinkedList) insertAfter(prev *node, V Elem) {
+	n := &node{V, nil, prev}
+
+	if prev == nil {
+		n.next = L.first
+		L.first = n
+	} else {
+		n.next = prev.next
+		prev.next = n
+	}
+
+	if n.next == nil {
+		L.last = n
+	} else {
+		n.next.prev = n
+	}
+	L.size++
+}
+
+// mergeSort sorts a chain of the given size by its next pointers,
+// and returns the new first node. It works bottom-up: it merges runs
+// of one node into runs of two, those into runs of four, and so on.
+func mergeSort(first *node, size int, less LessFunc) *node {
+	head := node{next: first}
+	for width := 1; width < size; width *= 2 {
+		tail, rest := &head, head.next
+		for rest != nil {
+			a := rest
+			b := cut(a, width)
+			rest = cut(b, width)
+
+			merged, last := merge(a, b, less)
+			tail.next = merged
+			tail = last
+		}
+	}
+	return head.next
+}
+
+// cut ends the chain after k nodes, and returns the rest of it.
+func cut(n *node, k int) *node {
+	for i := 1; n != nil && i < k; i++ {
+		n = n.next
+	}
+	if n == nil {
+		return nil
+	}
+
+	rest := n.next
+	n.next = nil
+	return rest
+}
+
+// merge merges two sorted chains by their next pointers, and returns
+// the first and last node. The elements of the first chain come first
+// among equal ones.
+func merge(a, b *node, less LessFunc) (*node, *node) {
+	var head node
+	tail := &head
+	for a != nil && b != nil {
+		if less(b.Value, a.Value) {
+			tail.next, b = b, b.next
+		} else {
+			tail.next, a = a, a.next
+		}
+		tail = tail.next
+	}
+
+	if a != nil {
+		tail.next = a
+	} else {
+		tail.next = b
+	}
+	for tail.next != nil {
+		tail = tail.next
+	}
+	return head.next, tail
+}
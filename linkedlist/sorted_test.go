@@ -0,0 +1,155 @@
+package linkedlist
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// pair is compared by key only, so the val tells equal elements apart.
+type pair struct{ key, val int }
+
+func pairLess(a, b interface{}) bool {
+	return a.(pair).key < b.(pair).key
+}
+
+// checkLinks reports broken prev pointers, a wrong last
+// node or a wrong size.
+func checkLinks(t *testing.T, list *LinkedList) {
+	size := 0
+	var prev *node
+	for n := list.first; n != nil; n = n.next {
+		if n.prev != prev {
+			t.Errorf("Broken prev pointer at %v.", n.Value)
+		}
+		prev = n
+		size++
+	}
+	if list.last != prev || list.size != size {
+		t.Errorf("The list ends or counts wrong.")
+	}
+}
+
+func TestInsert(t *testing.T) {
+	list := NewSorted(intLess)
+	for _, x := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		list.Insert(x)
+	}
+
+	checkLinks(t, &list.LinkedList)
+	if fmt.Sprint(list.ToSlice()) != "[1 1 2 3 4 5 6 9]" {
+		t.Errorf("Insert should keep the list sorted, got %v.", list.ToSlice())
+	}
+
+	stable := NewSorted(pairLess)
+	stable.Insert(pair{1, 0})
+	stable.Insert(pair{0, 0})
+	stable.Insert(pair{1, 1})
+	if stable.Last().(pair).val != 1 {
+		t.Errorf("Insert should place an element after equal ones.")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := NewSorted(pairLess), NewSorted(pairLess)
+	for _, k := range []int{1, 3, 5} {
+		a.Insert(pair{k, 0})
+	}
+	for _, k := range []int{0, 3, 4, 6} {
+		b.Insert(pair{k, 1})
+	}
+
+	a.Merge(b)
+	checkLinks(t, &a.LinkedList)
+	if got := fmt.Sprint(a.ToSlice()); got != "[{0 1} {1 0} {3 0} {3 1} {4 1} {5 0} {6 1}]" {
+		t.Errorf("Merge is broken, got %v.", got)
+	}
+	if b.Size() != 4 {
+		t.Errorf("Merge should leave the other list untouched.")
+	}
+
+	empty := NewSorted(pairLess)
+	empty.Merge(b)
+	if empty.Size() != 4 || empty.First() != (pair{0, 1}) || empty.Last() != (pair{6, 1}) {
+		t.Errorf("Merge into an empty list is broken.")
+	}
+
+	b.Merge(b)
+	checkLinks(t, &b.LinkedList)
+	if b.Size() != 8 {
+		t.Errorf("Merge of a list with itself is broken.")
+	}
+}
+
+func TestDedup(t *testing.T) {
+	list := NewSorted(pairLess)
+	for i, k := range []int{1, 2, 2, 3, 3, 3, 4} {
+		list.Insert(pair{k, i})
+	}
+
+	list.Dedup()
+	checkLinks(t, &list.LinkedList)
+	if got := fmt.Sprint(list.ToSlice()); got != "[{1 0} {2 1} {3 3} {4 6}]" {
+		t.Errorf("Dedup should keep the first of equal elements, got %v.", got)
+	}
+}
+
+func TestSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 70; n++ {
+		list := New()
+		for i := 0; i < n; i++ {
+			list.AddLast(pair{r.Intn(5), i})
+		}
+
+		list.Sort(pairLess)
+		checkLinks(t, list)
+
+		slc := list.ToSlice()
+		for i := 1; i < len(slc); i++ {
+			a, b := slc[i-1].(pair), slc[i].(pair)
+			if a.key > b.key || (a.key == b.key && a.val > b.val) {
+				t.Fatalf("Sort should be stable and sorted, got %v.", slc)
+			}
+		}
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	list := FromSlice([]pair{{2, 0}, {1, 1}, {2, 2}, {1, 3}})
+	list.SortStable(pairLess)
+
+	if got := fmt.Sprint(list.ToSlice()); got != "[{1 1} {1 3} {2 0} {2 2}]" {
+		t.Errorf("SortStable should keep equal elements in order, got %v.", got)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	if !New().IsSorted(intLess) || !FromSlice([]int{1, 2, 2, 3}).IsSorted(intLess) {
+		t.Errorf("IsSorted should accept a sorted list.")
+	}
+	if FromSlice([]int{1, 3, 2}).IsSorted(intLess) {
+		t.Errorf("IsSorted should refuse an unsorted list.")
+	}
+}
+
+func TestUnique(t *testing.T) {
+	eq := func(a, b interface{}) bool { return a == b }
+
+	list := FromSlice([]int{1, 1, 2, 1, 3, 3, 3})
+	list.Unique(eq)
+	checkLinks(t, list)
+	if got := fmt.Sprint(list.ToSlice()); got != "[1 2 1 3]" {
+		t.Errorf("Unique should remove runs of equal elements, got %v.", got)
+	}
+
+	list.Sort(intLess)
+	list.Unique(eq)
+	if got := fmt.Sprint(list.ToSlice()); got != "[1 2 3]" {
+		t.Errorf("Unique after Sort should remove every duplicate, got %v.", got)
+	}
+}
@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"context"
+	"reflect"
+)
+
+// FromChan builds a queue from the elements received on a channel,
+// in the order they arrive. It returns once the channel is closed.
+// The channel may be of any element type; FromChan panics if it
+// isn't a channel that can be received from.
+//
+// e.g. myqueue := queue.FromChan(jobs)
+//
+func FromChan(ch interface{}) *Queue {
+	Q := New()
+	v := reflect.ValueOf(ch)
+	if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.RecvDir == 0 {
+		panic("FromChan needs a channel to receive from.")
+	}
+	for {
+		x, ok := v.Recv()
+		if !ok {
+			return Q
+		}
+		Q.Offer(x.Interface())
+	}
+}
+
+// ToChan returns a channel which receives the elements of the queue,
+// polling them one at a time as they are sent. The channel is closed
+// once the queue is empty, or when the context is done; an element
+// which could not be sent by then is put back first in the queue.
+//
+// e.g. for x := range myqueue.ToChan(ctx) { x }
+//
+func (Q *Queue) ToChan(ctx context.Context) <-chan Elem {
+	ch := make(chan Elem)
+	go func() {
+		defer close(ch)
+		for {
+			V, ok := Q.TakeFirst()
+			if !ok {
+				return
+			}
+			select {
+			case ch <- V:
+			case <-ctx.Done():
+				Q.AddFirst(V)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Unbounded is a channel without a bound: sends on In never block,
+// as the elements wait in a Queue until Out is read.
+//
+// Closing In shuts it down: the elements still waiting are sent on
+// Out, which is then closed. When the context is done, Out is closed
+// at once and the elements still waiting are dropped; nothing reads
+// In anymore, so producers should stop as well.
+type Unbounded struct {
+	in  chan Elem
+	out chan Elem
+	buf *Queue
+}
+
+// NewUnbounded is used as a constructor for the Unbounded struct.
+// It starts a goroutine which lives until In is closed and drained,
+// or the context is done.
+//
+// e.g. ch := queue.NewUnbounded(ctx)
+//      ch.In() <- 1
+//      <-ch.Out() => 1
+//
+func NewUnbounded(ctx context.Context) *Unbounded {
+	U := &Unbounded{make(chan Elem), make(chan Elem), New()}
+	go U.run(ctx)
+	return U
+}
+
+// In returns the channel to send the elements on.
+func (U *Unbounded) In() chan<- Elem {
+	return U.in
+}
+
+// Out returns the channel to receive the elements from.
+func (U *Unbounded) Out() <-chan Elem {
+	return U.out
+}
+
+// Len returns the number of elements waiting to be received.
+func (U *Unbounded) Len() int {
+	return U.buf.Size()
+}
+
+// run moves the elements from In to the queue, and from
+// the queue to Out.
+func (U *Unbounded) run(ctx context.Context) {
+	defer close(U.out)
+
+	in := U.in
+	for in != nil || !U.buf.Empty() {
+		/* A nil channel never sends, so Out is left alone while empty */
+		var out chan Elem
+		var next Elem
+		if !U.buf.Empty() {
+			out, next = U.out, U.buf.Peek()
+		}
+
+		select {
+		case V, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			U.buf.Offer(V)
+		case out <- next:
+			U.buf.TakeFirst()
+		case <-ctx.Done():
+			U.buf.Clear()
+			return
+		}
+	}
+}
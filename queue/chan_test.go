@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+
+	queue := FromChan(ch)
+	if queue.Size() != 3 || queue.Poll() != 1 || queue.Poll() != 2 {
+		t.Errorf("FromChan should offer the elements in the order they arrive.")
+	}
+}
+
+func TestFromChanNotChan(t *testing.T) {
+	for _, x := range []interface{}{nil, 1, make(chan<- int)} {
+		func() {
+			defer func() {
+				if r := recover(); r != "FromChan needs a channel to receive from." {
+					t.Errorf("FromChan should panic clearly on %T, got %v.", x, r)
+				}
+			}()
+			FromChan(x)
+		}()
+	}
+}
+
+func TestToChan(t *testing.T) {
+	queue := New()
+	for i := 0; i < 5; i++ {
+		queue.Offer(i)
+	}
+
+	i := 0
+	for x := range queue.ToChan(context.Background()) {
+		if x != i {
+			t.Errorf("ToChan should send the elements in order.")
+		}
+		i++
+	}
+	if i != 5 || !queue.Empty() {
+		t.Errorf("ToChan should drain the queue.")
+	}
+}
+
+func TestToChanCancel(t *testing.T) {
+	queue := New()
+	for i := 0; i < 5; i++ {
+		queue.Offer(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := queue.ToChan(ctx)
+	if <-ch != 0 || <-ch != 1 {
+		t.Errorf("ToChan should send the elements in order.")
+	}
+	cancel()
+
+	n := 2
+	for range ch {
+		n++
+	}
+	if n+queue.Size() != 5 || (n < 5 && queue.Peek() != n) {
+		t.Errorf("ToChan should leave the elements it didn't send, got %v.", queue.ToSlice())
+	}
+}
+
+func TestToChanConcurrent(t *testing.T) {
+	queue := New()
+	for i := 0; i < 1000; i++ {
+		queue.Offer(i)
+	}
+
+	/* Several drains at once must each get distinct elements */
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	res := []Elem{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := range queue.ToChan(context.Background()) {
+				mu.Lock()
+				res = append(res, x)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[Elem]bool{}
+	for _, x := range res {
+		if x == nil || seen[x] {
+			t.Fatalf("ToChan should send every element once, got %v again.", x)
+		}
+		seen[x] = true
+	}
+	if len(seen) != 1000 || !queue.Empty() {
+		t.Errorf("ToChan should drain the queue, got %d.", len(seen))
+	}
+}
+
+func TestUnbounded(t *testing.T) {
+	u := NewUnbounded(context.Background())
+
+	/* Nobody reads yet, but sends don't block */
+	for i := 0; i < 1000; i++ {
+		u.In() <- i
+	}
+	close(u.In())
+
+	i := 0
+	for x := range u.Out() {
+		if x != i {
+			t.Fatalf("Out should return the elements in order, got %v want %d.", x, i)
+		}
+		i++
+	}
+	if i != 1000 || u.Len() != 0 {
+		t.Errorf("Closing In should drain the elements, got %d.", i)
+	}
+}
+
+func TestUnboundedCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnbounded(ctx)
+	for i := 0; i < 10; i++ {
+		u.In() <- i
+	}
+	if <-u.Out() != 0 {
+		t.Errorf("Out should return the first element.")
+	}
+	cancel()
+
+	select {
+	case <-waitClosed(u.Out()):
+	case <-time.After(time.Second):
+		t.Fatalf("Out should close when the context is done.")
+	}
+}
+
+// waitClosed returns a channel which is closed once ch is closed.
+func waitClosed(ch <-chan Elem) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	return done
+}
@@ -7,6 +7,10 @@ import "github.com/emnl/goods/linkedlist"
 
 // Queue uses a linkedlist to behave as a first-in-first-out
 // queue.
+//
+// The JSON, text, binary and gob encodings come from the linkedlist.
+// They list the elements from the first to be polled to the last, so
+// a decoded queue polls in the same order.
 type Queue struct {
 	linkedlist.LinkedList
 }
@@ -38,12 +42,7 @@ func (Q *Queue) Offer(V Elem) {
 //       --^-- .Poll() => 2
 //
 func (Q *Queue) Poll() Elem {
-	if Q.Empty() {
-		return nil
-	}
-
-	result := Q.First()
-	Q.RemoveFirst()
+	result, _ := Q.TakeFirst()
 	return result
 }
 
@@ -1,6 +1,9 @@
 package queue
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"testing"
 )
 
@@ -38,3 +41,55 @@ func TestPeek(t *testing.T) {
 		t.Errorf("Peek should return the first value, but not remove it.")
 	}
 }
+
+func TestMarshalJSON(t *testing.T) {
+	queue := New()
+	queue.Offer(1)
+	queue.Offer(2)
+
+	data, err := json.Marshal(queue)
+	if err != nil || string(data) != "[1,2]" {
+		t.Errorf("MarshalJSON should write the elements in polling order, got %s.", data)
+	}
+
+	queue = New()
+	json.Unmarshal([]byte(`["a","b"]`), queue)
+
+	if queue.Poll() != "a" || queue.Poll() != "b" {
+		t.Errorf("UnmarshalJSON should restore the polling order.")
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	queue := New()
+	queue.Offer(1)
+	queue.Offer(2)
+
+	data, err := queue.MarshalText()
+	if err != nil || string(data) != "[1,2]" {
+		t.Errorf("MarshalText should write the elements in the same order as MarshalJSON, got %s.", data)
+	}
+
+	res := New()
+	if res.UnmarshalText(data) != nil || res.Poll() != 1.0 {
+		t.Errorf("UnmarshalText should restore the order.")
+	}
+}
+
+func TestGob(t *testing.T) {
+	queue := New()
+	queue.Offer(1)
+	queue.Offer(2)
+
+	buf := new(bytes.Buffer)
+	gob.NewEncoder(buf).Encode(queue)
+
+	queue = New()
+	if err := gob.NewDecoder(buf).Decode(queue); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+
+	if queue.Poll() != 1 || queue.Poll() != 2 {
+		t.Errorf("GobDecode should restore the polling order.")
+	}
+}
@@ -0,0 +1,76 @@
+package redblacktree
+
+// AugmentFunc is used as a user function to augment the nodes of the
+// Tree with a value that summarizes their subtree, like the biggest
+// endpoint in an interval tree. Given the element of a node and the
+// values of its left and right subtrees (nil for an empty subtree),
+// it must return the value of the node's subtree.
+//
+// The values are kept up to date through every insert, delete and
+// rotation, so the function must only depend on its parameters.
+//
+// e.g. sum func(E Elem, l, r interface{}) interface{} {
+//          s := E.(int)
+//          if l != nil { s += l.(int) }
+//          if r != nil { s += r.(int) }
+//          return s
+//      }
+//
+type AugmentFunc func(E Elem, left, right interface{}) interface{}
+
+// NewAugmented is used as a constructor for a Tree whose nodes
+// are augmented by the given function.
+//
+// e.g. mytree := redblacktree.NewAugmented(intLess, sum)
+//
+func NewAugmented(lf LessFunc, af AugmentFunc) *RedBlackTree {
+	rbt := RedBlackTree{lf, 0, nil, af, nil, nil}
+	return &rbt
+}
+
+// A Cursor points at a node in the Tree, or at a leaf. It is used to
+// search an augmented Tree from the root and down. A Cursor is only
+// valid until the Tree is modified.
+type Cursor struct {
+	n *node
+}
+
+// Root returns a Cursor pointing at the root of the Tree.
+func (T *RedBlackTree) Root() Cursor {
+	return Cursor{T.root}
+}
+
+// Empty returns true if the Cursor points at a leaf.
+func (C Cursor) Empty() bool {
+	return C.n == nil
+}
+
+// Elem returns the element of the node, or nil at a leaf.
+func (C Cursor) Elem() Elem {
+	if C.n == nil {
+		return nil
+	}
+	return C.n.elem
+}
+
+// Augmented returns the augmented value of the node's
+// subtree, or nil at a leaf.
+func (C Cursor) Augmented() interface{} {
+	return augOf(C.n)
+}
+
+// Left returns a Cursor pointing at the left (smaller) child.
+func (C Cursor) Left() Cursor {
+	if C.n == nil {
+		return C
+	}
+	return Cursor{C.n.left}
+}
+
+// Right returns a Cursor pointing at the right (bigger) child.
+func (C Cursor) Right() Cursor {
+	if C.n == nil {
+		return C
+	}
+	return Cursor{C.n.right}
+}
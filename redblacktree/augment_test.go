@@ -0,0 +1,84 @@
+package redblacktree
+
+import "testing"
+
+func sum(E Elem, l, r interface{}) interface{} {
+	s := E.(int)
+	if l != nil {
+		s += l.(int)
+	}
+	if r != nil {
+		s += r.(int)
+	}
+	return s
+}
+
+// checkSums checks the augmented value of every node in the subtree.
+func checkSums(t *testing.T, c Cursor) int {
+	if c.Empty() {
+		return 0
+	}
+	s := c.Elem().(int) + checkSums(t, c.Left()) + checkSums(t, c.Right())
+	if c.Augmented() != s {
+		t.Errorf("Wrong augmented value at %v.", c.Elem())
+	}
+	return s
+}
+
+func TestAugmented(t *testing.T) {
+	tree := NewAugmented(intLess, sum)
+
+	if !tree.Root().Empty() || tree.Root().Augmented() != nil {
+		t.Errorf("An empty tree should have an empty root.")
+	}
+
+	total := 0
+	for x := 0; x < 200; x++ {
+		if tree.Add((x*37)%200) == nil {
+			total += (x * 37) % 200
+		}
+		checkSums(t, tree.Root())
+	}
+	for x := 0; x < 200; x += 3 {
+		tree.Remove((x * 11) % 200)
+		total -= (x * 11) % 200
+		checkSums(t, tree.Root())
+	}
+	checkTree(t, tree)
+
+	if tree.Root().Augmented() != total {
+		t.Errorf("The root should hold the sum of every element.")
+	}
+}
+
+func TestAugmentedSplitJoin(t *testing.T) {
+	tree := NewAugmented(intLess, sum)
+	for x := 0; x < 100; x++ {
+		tree.Add(x)
+	}
+
+	left, right := tree.Split(40)
+	checkSums(t, left.Root())
+	checkSums(t, right.Root())
+
+	other := NewAugmented(intLess, sum)
+	for x := 30; x < 60; x++ {
+		other.Add(x)
+	}
+	left.Union(other)
+	checkSums(t, left.Root())
+	checkTree(t, left)
+
+	right.Difference(other)
+	checkSums(t, right.Root())
+
+	joined, err := Join(left, right)
+	if err != nil {
+		t.Fatalf("Join should accept trees that don't overlap.")
+	}
+	checkSums(t, joined.Root())
+
+	if joined.Root().Augmented() != 99*100/2 {
+		t.Errorf("The root should hold the sum of every element.")
+	}
+}
@@ -0,0 +1,252 @@
+package redblacktree
+
+import "errors"
+
+// Split divides the Tree in two: one Tree with the elements less than
+// the given element, and one with the elements equal or bigger. The
+// nodes are moved to the new Trees, leaving the Tree empty.
+// It runs in O(log n).
+//
+// e.g. (2 (1) (3)).Split(2) => (1 () ()), (3 (2) ())
+//
+func (T *RedBlackTree) Split(E Elem) (*RedBlackTree, *RedBlackTree) {
+	l, _, found, r, rh := T.split(T.root, blackHeight(T.root), E)
+	if found != nil {
+		r, _ = T.join(nil, 0, found, r, rh)
+	}
+
+	left, right := T.blank(), T.blank()
+	left.setRoot(l)
+	right.setRoot(r)
+	if T.size > 0 {
+		T.setRoot(nil)
+		T.notify(OpReset, nil)
+	}
+
+	return left, right
+}
+
+// Join concatenates two Trees into one. Every element in left must
+// be less than every element in right, and both Trees must use the
+// same LessFunc; the new Tree uses the one of left. The nodes are
+// moved to the new Tree, leaving both Trees empty. It runs in O(log n).
+//
+// e.g. Join((1 () ()), (3 (2) ())) => (2 (1) (3))
+//
+func Join(left, right *RedBlackTree) (*RedBlackTree, error) {
+	if left == right {
+		return nil, errors.New("Can't join a Tree with itself.")
+	}
+	if !left.Empty() && !right.Empty() && !left.less(left.Last(), right.First()) {
+		return nil, errors.New("Trees overlap.")
+	}
+
+	T := left.blank()
+	root, _ := T.join2(left.root, blackHeight(left.root), right.root, blackHeight(right.root))
+	T.setRoot(root)
+	for _, t := range []*RedBlackTree{left, right} {
+		if t.size > 0 {
+			t.setRoot(nil)
+			t.notify(OpReset, nil)
+		}
+	}
+
+	return T, nil
+}
+
+// Split and join are the building blocks for the bulk operations
+// on the Tree. They work on detached subtrees, identified by their
+// root node and their black height: the number of black nodes on
+// any path from the root down to a leaf (the root included).
+
+// blackHeight returns the black height of the subtree.
+func blackHeight(n *node) int {
+	h := 0
+	for ; n != nil; n = n.left {
+		if !n.red {
+			h++
+		}
+	}
+	return h
+}
+
+// detach cuts the node loose from its children and
+// returns them.
+func (N *node) detach() (*node, *node) {
+	left, right := N.left, N.right
+	if left != nil {
+		left.parent = nil
+	}
+	if right != nil {
+		right.parent = nil
+	}
+	N.left, N.right, N.parent = nil, nil, nil
+	N.size = 1
+	return left, right
+}
+
+// clone returns a copy of the subtree, which may belong
+// to another Tree.
+func (T *RedBlackTree) clone(n *node) *node {
+	if n == nil {
+		return nil
+	}
+	c := &node{n.elem, T.clone(n.left), T.clone(n.right), nil, n.red, 1, nil}
+	if c.left != nil {
+		c.left.parent = c
+	}
+	if c.right != nil {
+		c.right.parent = c
+	}
+	T.update(c)
+	return c
+}
+
+// join links the subtrees l and r together with the detached node k
+// in between. Every element in l must be less than k, and every element
+// in r bigger. It returns the new root and its black height.
+//
+// The node is hung on the spine of the higher subtree, where the black
+// heights match, and the tree is repaired like after an insert. This
+// takes time proportional to the difference of the black heights.
+func (T *RedBlackTree) join(l *node, lh int, k *node, r *node, rh int) (*node, int) {
+	if isRed(l) {
+		l.red = false
+		lh++
+	}
+	if isRed(r) {
+		r.red = false
+		rh++
+	}
+
+	if lh == rh {
+		k.left, k.right, k.red = l, r, false
+		if l != nil {
+			l.parent = k
+		}
+		if r != nil {
+			r.parent = k
+		}
+		T.update(k)
+		return k, lh + 1
+	}
+
+	if lh > rh {
+		t := RedBlackTree{less: T.less, root: l, augment: T.augment}
+
+		var p *node
+		c, h := l, lh
+		for c != nil && (c.red || h != rh) {
+			if !c.red {
+				h--
+			}
+			p, c = c, c.right
+		}
+
+		k.left, k.right, k.parent, k.red = c, r, p, true
+		if c != nil {
+			c.parent = k
+		}
+		if r != nil {
+			r.parent = k
+		}
+		p.right = k
+		t.updatePath(k)
+
+		if t.joinFixup(k) {
+			lh++
+		}
+		return t.root, lh
+	}
+
+	t := RedBlackTree{less: T.less, root: r, augment: T.augment}
+
+	var p *node
+	c, h := r, rh
+	for c != nil && (c.red || h != lh) {
+		if !c.red {
+			h--
+		}
+		p, c = c, c.left
+	}
+
+	k.left, k.right, k.parent, k.red = l, c, p, true
+	if c != nil {
+		c.parent = k
+	}
+	if l != nil {
+		l.parent = k
+	}
+	p.left = k
+	t.updatePath(k)
+
+	if t.joinFixup(k) {
+		rh++
+	}
+	return t.root, rh
+}
+
+// joinFixup repairs the Tree after a red node has been linked in by
+// join. It returns true if the root had to be repainted, meaning that
+// the black height of the Tree grew by one.
+func (T *RedBlackTree) joinFixup(n *node) bool {
+	for {
+		if n.parent == nil {
+			n.red = false
+			return true
+		}
+		if !isRed(n.parent) {
+			return false
+		}
+		if isRed(n.uncle()) {
+			n.parent.red = false
+			n.uncle().red = false
+			n.grandparent().red = true
+			n = n.grandparent()
+			continue
+		}
+		T.insertCase4(n)
+		return false
+	}
+}
+
+// join2 links the subtrees l and r together. Every element in l must
+// be less than every element in r.
+func (T *RedBlackTree) join2(l *node, lh int, r *node, rh int) (*node, int) {
+	if l == nil {
+		return r, rh
+	}
+	if r == nil {
+		return l, lh
+	}
+	l, lh, k, _, _ := T.split(l, lh, l.findMax().elem)
+	return T.join(l, lh, k, r, rh)
+}
+
+// split takes the subtree n apart around the given element. It returns
+// the subtree with the smaller elements, the node holding the element
+// (nil if it isn't found), and the subtree with the bigger elements,
+// along with the black heights of both subtrees.
+func (T *RedBlackTree) split(n *node, h int, E Elem) (l *node, lh int, found *node, r *node, rh int) {
+	if n == nil {
+		return nil, 0, nil, nil, 0
+	}
+
+	ch := h // black height of the children
+	if !n.red {
+		ch--
+	}
+	left, right := n.detach()
+
+	switch {
+	case T.less(E, n.elem):
+		l, lh, found, r, rh = T.split(left, ch, E)
+		r, rh = T.join(r, rh, n, right, ch)
+	case T.less(n.elem, E):
+		l, lh, found, r, rh = T.split(right, ch, E)
+		l, lh = T.join(left, ch, n, l, lh)
+	default:
+		l, lh, found, r, rh = left, ch, n, right, ch
+	}
+	return
+}
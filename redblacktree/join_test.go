@@ -0,0 +1,81 @@
+package redblacktree
+
+import "testing"
+
+func TestSplit(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		for key := -1; key <= n*2+1; key++ {
+			slc := make([]int, n)
+			for i := range slc {
+				slc[i] = i * 2
+			}
+			tree, _ := FromSorted(intLess, slc)
+
+			left, right := tree.Split(key)
+			checkTree(t, left)
+			checkTree(t, right)
+
+			if !tree.Empty() {
+				t.Errorf("Split should leave the tree empty.")
+			}
+			if left.Size()+right.Size() != n {
+				t.Errorf("Split should keep every element.")
+			}
+			for x := range left.InOrder() {
+				if x.(int) >= key {
+					t.Errorf("The left tree should only hold smaller elements.")
+				}
+			}
+			for x := range right.InOrder() {
+				if x.(int) < key {
+					t.Errorf("The right tree should only hold equal or bigger elements.")
+				}
+			}
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	for n := 0; n < 30; n++ {
+		for m := 0; m < 30; m++ {
+			left, right := New(intLess), New(intLess)
+			for x := 0; x < n; x++ {
+				left.Add(x)
+			}
+			for x := 0; x < m; x++ {
+				right.Add(n + x)
+			}
+
+			tree, err := Join(left, right)
+			if err != nil {
+				t.Fatalf("Join should accept trees that don't overlap.")
+			}
+			checkTree(t, tree)
+
+			res := toSlice(tree)
+			if len(res) != n+m {
+				t.Errorf("Join should keep every element.")
+			}
+			for i, x := range res {
+				if x != i {
+					t.Errorf("Join should keep the elements in order.")
+				}
+			}
+			if !left.Empty() || !right.Empty() {
+				t.Errorf("Join should leave both trees empty.")
+			}
+		}
+	}
+
+	left := FromSlice(intLess, []int{1, 5})
+	right := FromSlice(intLess, []int{3, 7})
+	if _, err := Join(left, right); err == nil {
+		t.Errorf("Join should refuse overlapping trees.")
+	}
+	if left.Size() != 2 || right.Size() != 2 {
+		t.Errorf("A failed Join should leave the trees untouched.")
+	}
+	if _, err := Join(left, left); err == nil || left.Size() != 2 {
+		t.Errorf("Join should refuse to join a Tree with itself.")
+	}
+}
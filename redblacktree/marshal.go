@@ -0,0 +1,152 @@
+package redblacktree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The binary format starts with a magic string and a version byte,
+// followed by the elements in order, encoded with encoding/gob.
+// Elements of user defined types must be registered with gob.Register.
+const (
+	magic   = "GRBT"
+	version = 1
+)
+
+// payload is the gob encoded part of the binary format.
+type payload struct {
+	Size  int
+	Elems []Elem
+}
+
+// SetElemType tells UnmarshalJSON which type the elements have.
+// Without it, JSON numbers are decoded as float64, objects as
+// map[string]interface{} and so on.
+//
+// e.g. mytree.SetElemType(0) // the elements are ints
+//
+func (T *RedBlackTree) SetElemType(example Elem) {
+	T.elemType = reflect.TypeOf(example)
+}
+
+// MarshalBinary encodes the elements of the Tree in order.
+// It implements encoding.BinaryMarshaler.
+func (T *RedBlackTree) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString(magic)
+	buf.WriteByte(version)
+
+	if err := gob.NewEncoder(buf).Encode(payload{T.size, T.elems()}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the elements of the Tree with the ones
+// encoded by MarshalBinary. The Tree must have a LessFunc, so it should
+// be created with New first. It implements encoding.BinaryUnmarshaler.
+//
+// e.g. mytree := redblacktree.New(intLess)
+//      err := mytree.UnmarshalBinary(data)
+//
+func (T *RedBlackTree) UnmarshalBinary(data []byte) error {
+	if len(data) < len(magic)+1 || string(data[:len(magic)]) != magic {
+		return errors.New("Data is not an encoded Tree.")
+	}
+	if data[len(magic)] != version {
+		return fmt.Errorf("Unsupported Tree encoding version %d.", data[len(magic)])
+	}
+
+	var p payload
+	if err := gob.NewDecoder(bytes.NewReader(data[len(magic)+1:])).Decode(&p); err != nil {
+		return fmt.Errorf("Corrupt Tree data: %v", err)
+	}
+	if p.Size != len(p.Elems) {
+		return errors.New("Corrupt Tree data: size mismatch.")
+	}
+
+	return T.restore(p.Elems)
+}
+
+// MarshalJSON encodes the Tree as a JSON array of its elements,
+// in order. It implements json.Marshaler.
+//
+// e.g. (2 (1) (3)) => [1,2,3]
+//
+func (T *RedBlackTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(T.elems())
+}
+
+// UnmarshalJSON replaces the elements of the Tree with the ones in a
+// JSON array. The Tree must have a LessFunc, and SetElemType decides
+// the type of the elements. It implements json.Unmarshaler.
+//
+// e.g. mytree := redblacktree.New(intLess)
+//      mytree.SetElemType(0)
+//      err := json.Unmarshal([]byte("[1,2,3]"), mytree)
+//
+func (T *RedBlackTree) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("Corrupt Tree data: %v", err)
+	}
+
+	elems := make([]Elem, len(raw))
+	for i, r := range raw {
+		if T.elemType == nil {
+			if err := json.Unmarshal(r, &elems[i]); err != nil {
+				return fmt.Errorf("Corrupt Tree data: %v", err)
+			}
+			continue
+		}
+
+		v := reflect.New(T.elemType)
+		if err := json.Unmarshal(r, v.Interface()); err != nil {
+			return fmt.Errorf("Corrupt Tree data: %v", err)
+		}
+		elems[i] = v.Elem().Interface()
+	}
+
+	return T.restore(elems)
+}
+
+// elems returns the elements of the Tree in order.
+func (T *RedBlackTree) elems() []Elem {
+	res := make([]Elem, 0, T.size)
+	inOrder(T.root, func(n *node) {
+		res = append(res, n.elem)
+	})
+	return res
+}
+
+// restore replaces the elements of the Tree with the given ones,
+// which must be sorted. The Tree is left untouched on failure.
+func (T *RedBlackTree) restore(elems []Elem) (err error) {
+	if T.less == nil {
+		return errors.New("The Tree has no LessFunc.")
+	}
+
+	/* The LessFunc may panic on elements of an unexpected type */
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Corrupt Tree data: %v", r)
+		}
+	}()
+
+	for i := 1; i < len(elems); i++ {
+		if !T.less(elems[i-1], elems[i]) {
+			return errors.New("Corrupt Tree data: elements are not sorted.")
+		}
+	}
+
+	if T.size == 0 && len(elems) == 0 {
+		return nil
+	}
+	T.setRoot(T.build(elems))
+	T.notify(OpReset, nil)
+	return nil
+}
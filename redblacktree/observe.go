@@ -0,0 +1,113 @@
+package redblacktree
+
+// Op is the kind of change an Event reports.
+type Op int
+
+const (
+	OpAdd    Op = iota // Elem was added
+	OpRemove           // Elem was removed
+	OpSet              // Elem replaced an equal element
+	OpClear            // every element was removed
+	OpReset            // the Tree was changed as a whole
+)
+
+// String returns the name of the operation.
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "Add"
+	case OpRemove:
+		return "Remove"
+	case OpSet:
+		return "Set"
+	case OpClear:
+		return "Clear"
+	case OpReset:
+		return "Reset"
+	}
+	return "Unknown"
+}
+
+// Event reports a change to the Tree. Elem is the added, removed or
+// new element, and nil for OpClear and OpReset. Seq grows by one with
+// every change, whether anyone listens or not, so a subscriber can
+// tell the order of the events and how many changes came before.
+//
+// Add, Remove and Clear send an event per change. Methods which change
+// many elements at once, such as Split, Join, the set operations and
+// the Unmarshal methods, send a single OpReset instead; a subscriber
+// which mirrors the Tree must then read it again. Changes which leave
+// the Tree as it was send nothing.
+type Event struct {
+	Op   Op
+	Elem Elem
+	Seq  uint64
+}
+
+// watchers are the subscribers of a Tree.
+type watchers struct {
+	subs   []subscriber
+	nextID int
+	seq    uint64
+}
+
+// subscriber is a function registered by Subscribe.
+type subscriber struct {
+	id int
+	f  func(Event)
+}
+
+// Subscribe registers a function which is called with every change
+// to the Tree, and returns a function which stops the calls. The
+// function is called once the change is done, so it may use the Tree.
+//
+// e.g. stop := mytree.Subscribe(func(e redblacktree.Event) { ... })
+//      defer stop()
+//
+func (T *RedBlackTree) Subscribe(f func(Event)) func() {
+	if T.watch == nil {
+		T.watch = &watchers{}
+	}
+	w := T.watch
+
+	w.nextID++
+	id := w.nextID
+	w.subs = append(w.subs, subscriber{id, f})
+
+	return func() {
+		for i, s := range w.subs {
+			if s.id == id {
+				w.subs = append(w.subs[:i:i], w.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Clear removes every element from the Tree.
+//
+// e.g. (2 (1) (3)).Clear() => ()
+//
+func (T *RedBlackTree) Clear() {
+	if T.size == 0 {
+		return
+	}
+	T.setRoot(nil)
+	T.notify(OpClear, nil)
+}
+
+// notify counts a change, and calls the subscribers with it.
+func (T *RedBlackTree) notify(op Op, E Elem) {
+	if T.watch == nil {
+		T.watch = &watchers{}
+	}
+	T.watch.seq++
+	if len(T.watch.subs) == 0 {
+		return
+	}
+
+	e := Event{op, E, T.watch.seq}
+	for _, s := range T.watch.subs {
+		s.f(e)
+	}
+}
@@ -0,0 +1,111 @@
+package redblacktree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	tree := New(intLess)
+	tree.Add(1)
+
+	events := []Event{}
+	stop := tree.Subscribe(func(e Event) {
+		events = append(events, e)
+		if tree.Size() < 0 {
+			t.Errorf("A subscriber should be able to use the tree.")
+		}
+	})
+
+	tree.Add(2)
+	tree.Add(2)
+	tree.Remove(1)
+	tree.Remove(5)
+	tree.Clear()
+
+	if got := fmt.Sprint(events); got != "[{Add 2 2} {Set 2 3} {Remove 1 4} {Clear <nil> 5}]" {
+		t.Errorf("Subscribe got %v.", got)
+	}
+	if !tree.Empty() {
+		t.Errorf("Clear should empty the tree.")
+	}
+
+	stop()
+	tree.Add(3)
+	if len(events) != 4 {
+		t.Errorf("No events should be sent after unsubscribing.")
+	}
+
+	/* Seq counts the changes made while nobody listened */
+	tree.Subscribe(func(e Event) { events = append(events, e) })
+	tree.Add(4)
+	if e := events[len(events)-1]; e.Seq != 7 {
+		t.Errorf("Seq should grow with every change, got %d.", e.Seq)
+	}
+}
+
+func TestSubscribeUnsubscribeInside(t *testing.T) {
+	tree := New(intLess)
+
+	calls := 0
+	var stop func()
+	stop = tree.Subscribe(func(e Event) {
+		calls++
+		stop()
+	})
+	tree.Subscribe(func(e Event) { calls++ })
+
+	tree.Add(1)
+	tree.Add(2)
+	if calls != 3 {
+		t.Errorf("Unsubscribing inside a subscriber is broken, got %d calls.", calls)
+	}
+}
+
+func TestSubscribeReset(t *testing.T) {
+	tree, other := New(intLess), New(intLess)
+	for _, x := range []int{1, 2, 3} {
+		tree.Add(x)
+	}
+	other.Add(3)
+	other.Add(4)
+	tree.SetElemType(0)
+
+	ops := []Op{}
+	tree.Subscribe(func(e Event) { ops = append(ops, e.Op) })
+
+	changes := []func(){
+		func() { tree.Union(other) },
+		func() { tree.Difference(other) },
+		func() { tree.SymmetricDifference(other) },
+		func() { tree.Intersection(other) },
+		func() { tree.UnmarshalJSON([]byte("[1,2,3]")) },
+		func() { tree.Split(2) },
+	}
+	for i, change := range changes {
+		change()
+		if len(ops) != i+1 || ops[i] != OpReset {
+			t.Fatalf("Change %d should send an OpReset, got %v.", i, ops)
+		}
+	}
+
+	/* Changes which leave the Tree as it was send nothing */
+	tree.Clear()
+	tree.Union(New(intLess))
+	tree.UnmarshalJSON([]byte("[]"))
+	tree.Split(2)
+	if len(ops) != len(changes) {
+		t.Errorf("A change that changes nothing should send no event, got %v.", ops)
+	}
+
+	left, right := New(intLess), New(intLess)
+	left.Add(1)
+	right.Add(2)
+	joined := 0
+	left.Subscribe(func(e Event) { joined++ })
+	right.Subscribe(func(e Event) { joined++ })
+	Join(left, right)
+	if joined != 2 {
+		t.Errorf("Join should send an OpReset to both Trees, got %d.", joined)
+	}
+}
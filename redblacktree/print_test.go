@@ -0,0 +1,103 @@
+package redblacktree
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFprint(t *testing.T) {
+	tree := New(intLess)
+	tree.Add(2)
+	tree.Add(1)
+	tree.Add(3)
+
+	buf := new(bytes.Buffer)
+	tree.Fprint(buf, &PrintOptions{Colors: true})
+
+	if buf.String() != "-----(3) \n|2| \n-----(1) \n" {
+		t.Errorf("Fprint should draw the tree sideways by default, got:\n%s", buf)
+	}
+
+	buf.Reset()
+	tree.Fprint(buf, &PrintOptions{Layout: TopDown})
+
+	if buf.String() != " _2_\n/   \\\n1   3\n" {
+		t.Errorf("Fprint should draw the tree top-down, got:\n%s", buf)
+	}
+
+	buf.Reset()
+	New(intLess).Fprint(buf, nil)
+
+	if buf.String() != "Empty tree\n" {
+		t.Errorf("Fprint should say that the tree is empty.")
+	}
+}
+
+func TestFprintFormat(t *testing.T) {
+	tree := New(func(a, b interface{}) bool { return a.(string) < b.(string) })
+	tree.Add("b")
+	tree.Add("a")
+
+	buf := new(bytes.Buffer)
+	tree.Fprint(buf, nil)
+
+	if buf.String() != "b \n-----a \n" {
+		t.Errorf("Fprint should print strings, got:\n%s", buf)
+	}
+
+	buf.Reset()
+	tree.Fprint(buf, &PrintOptions{Format: func(E Elem) string { return strings.ToUpper(E.(string)) }})
+
+	if buf.String() != "B \n-----A \n" {
+		t.Errorf("Fprint should use the given Format, got:\n%s", buf)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	tree := New(intLess)
+	tree.Add(2)
+	tree.Add(1)
+
+	buf := new(bytes.Buffer)
+	tree.WriteDOT(buf, nil)
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "digraph tree {") || !strings.HasSuffix(out, "}\n") {
+		t.Errorf("WriteDOT should write a digraph.")
+	}
+	if !strings.Contains(out, `n0 [label="1", fillcolor=red];`) {
+		t.Errorf("WriteDOT should write red nodes.")
+	}
+	if !strings.Contains(out, `n1 [label="2", fillcolor=black];`) {
+		t.Errorf("WriteDOT should write black nodes.")
+	}
+	if !strings.Contains(out, "n1 -> n0;") {
+		t.Errorf("WriteDOT should write the edges.")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tree := New(intLess)
+	tree.Add(2)
+	tree.Add(1)
+
+	buf := new(bytes.Buffer)
+	tree.WriteJSON(buf, nil)
+
+	var res map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("WriteJSON should write valid JSON.")
+	}
+	if res["elem"] != 2.0 || res["color"] != "black" {
+		t.Errorf("WriteJSON should start at the root.")
+	}
+	left := res["left"].(map[string]interface{})
+	if left["elem"] != 1.0 || left["color"] != "red" {
+		t.Errorf("WriteJSON should write the left child.")
+	}
+	if _, ok := res["right"]; ok {
+		t.Errorf("WriteJSON should leave out missing children.")
+	}
+}
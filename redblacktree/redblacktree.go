@@ -4,14 +4,18 @@ package redblacktree
 
 import (
 	"errors"
-	"fmt"
 	"github.com/emnl/goods/queue"
 	"github.com/emnl/goods/stack"
 	"math"
+	"reflect"
+	"sort"
 )
 
-// A redblacktree has a size, a pointer to the root node, and
-// a user defined function which is used to compare the node's element.
+// A redblacktree has a size, a pointer to the root node,
+// a user defined function which is used to compare the node's element,
+// optionally a user defined function which augments the nodes,
+// optionally the type of the elements, used when decoding JSON,
+// and the subscribers to its changes, if there are any.
 //
 // It has the following requirements:
 // 1. A node is either red or black.
@@ -22,20 +26,27 @@ import (
 //    contains the same number of black nodes.
 //
 type RedBlackTree struct {
-	less LessFunc
-	size int
-	root *node
+	less     LessFunc
+	size     int
+	root     *node
+	augment  AugmentFunc
+	elemType reflect.Type
+	watch    *watchers
 }
 
 // The redblacktree is made up of nodes with an element,
 // a pointer to the left (smaller) node, a pointer to the right (bigger) node,
-// a pointer to the parent node, and a color (red/black).
+// a pointer to the parent node, a color (red/black), the number of
+// nodes in the subtree rooted at the node, and the augmented value
+// of the subtree.
 type node struct {
 	elem   Elem
 	left   *node
 	right  *node
 	parent *node
 	red    bool
+	size   int
+	aug    interface{}
 }
 
 // Elem is used as a generic for any type of value.
@@ -55,10 +66,62 @@ type LessFunc func(a, b interface{}) bool
 // e.g. mytree := redblacktree.New(intLess)
 //
 func New(lf LessFunc) *RedBlackTree {
-	rbt := RedBlackTree{lf, 0, nil}
+	rbt := RedBlackTree{lf, 0, nil, nil, nil, nil}
 	return &rbt
 }
 
+// FromSorted builds a balanced Tree from a go slice whose elements
+// are sorted in strictly ascending order. It runs in linear time.
+//
+// e.g. mytree, err := redblacktree.FromSorted(intLess, []int{1, 2, 3})
+//
+func FromSorted(lf LessFunc, slc interface{}) (*RedBlackTree, error) {
+	v := reflect.ValueOf(slc)
+	elems := make([]Elem, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elems[i] = v.Index(i).Interface()
+		if i > 0 && !lf(elems[i-1], elems[i]) {
+			return nil, errors.New("Slice is not sorted.")
+		}
+	}
+
+	T := New(lf)
+	T.setRoot(T.build(elems))
+	return T, nil
+}
+
+// FromSlice builds a balanced Tree from a go slice in any order.
+// Like Add, a later element replaces an earlier equal one.
+//
+// e.g. mytree := redblacktree.FromSlice(intLess, []int{3, 1, 2})
+//
+func FromSlice(lf LessFunc, slc interface{}) *RedBlackTree {
+	v := reflect.ValueOf(slc)
+	elems := make([]Elem, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elems[i] = v.Index(i).Interface()
+	}
+
+	sort.SliceStable(elems, func(i, j int) bool {
+		return lf(elems[i], elems[j])
+	})
+
+	/* Keep the last element of every run of equal elements */
+	uniq := elems[:0]
+	for i, e := range elems {
+		if i+1 < len(elems) && !lf(e, elems[i+1]) {
+			continue
+		}
+		uniq = append(uniq, e)
+	}
+
+	T := New(lf)
+	T.setRoot(T.build(uniq))
+	return T
+}
+
 // Size returns the size of the Tree.
 //
 // e.g. (2 (1) (3)).Size() => 3
@@ -85,8 +148,10 @@ func (T *RedBlackTree) Add(E Elem) error {
 	oldsize := T.size
 	T.insert(E)
 	if oldsize == T.size {
+		T.notify(OpSet, E)
 		return errors.New("Item already exists in Tree.")
 	}
+	T.notify(OpAdd, E)
 	return nil
 }
 
@@ -96,11 +161,14 @@ func (T *RedBlackTree) Add(E Elem) error {
 // e.g. (2 (1) (3)).Remove(2) => (1 () (3))
 //
 func (T *RedBlackTree) Remove(E Elem) error {
-	oldsize := T.size
-	T.delete(E)
-	if oldsize == T.size {
+	n := T.get(E)
+	if n == nil {
 		return errors.New("Item not found in Tree.")
 	}
+
+	removed := n.elem
+	T.delete(E)
+	T.notify(OpRemove, removed)
 	return nil
 }
 
@@ -114,6 +182,18 @@ func (T *RedBlackTree) Contains(E Elem) bool {
 	return T.get(E) != nil
 }
 
+// Get returns the element in the Tree that is equal to the
+// given one, or nil if there is none.
+//
+// e.g. (2 (1) (3)).Get(1) => 1
+//
+func (T *RedBlackTree) Get(E Elem) Elem {
+	if n := T.get(E); n != nil {
+		return n.elem
+	}
+	return nil
+}
+
 // First returns the left-most (smallest) element in the Tree.
 //
 // e.g. (2 (1) (3)).First() => 1
@@ -301,18 +381,6 @@ func (T *RedBlackTree) LevelOrder() chan Elem {
 	return ch
 }
 
-// PrintTree prints the tree in the console. It is used as a
-// debugging tool.
-func (T *RedBlackTree) PrintTree() {
-	if T.Empty() {
-		fmt.Println("Empty tree")
-		return
-	}
-	fmt.Print("\n")
-	print(T.root, 0)
-	fmt.Print("\n")
-}
-
 // isRed returns true if the given node is red.
 // The leafs of a redblacktree are always considered black,
 // therefore nil return false. This is important.
@@ -361,6 +429,8 @@ func (T *RedBlackTree) rotateLeft(n *node) {
 	}
 	right.left = n
 	n.parent = right
+	T.update(n)
+	T.update(right)
 }
 
 // rotateRight replaces the given node with the left node
@@ -385,6 +455,8 @@ func (T *RedBlackTree) rotateRight(n *node) {
 	}
 	left.right = n
 	n.parent = left
+	T.update(n)
+	T.update(left)
 }
 
 // replaceNode replaces an old node for a new one and
@@ -408,7 +480,7 @@ func (T *RedBlackTree) replaceNode(oldn, newn *node) {
 // it into the Tree. A new node is always inserted as
 // red.
 func (T *RedBlackTree) insert(E Elem) {
-	newn := &node{E, nil, nil, nil, true}
+	newn := &node{E, nil, nil, nil, true, 1, nil}
 
 	if T.root == nil {
 		T.root = newn
@@ -431,12 +503,14 @@ func (T *RedBlackTree) insert(E Elem) {
 				}
 			} else {
 				n.elem = newn.elem
+				T.updatePath(n)
 				return
 			}
 		}
 		newn.parent = n
 	}
 
+	T.updatePath(newn)
 	T.size += 1 // A node will be added
 	T.insertCase1(newn)
 }
@@ -514,6 +588,7 @@ func (T *RedBlackTree) delete(E Elem) {
 	if dnode.left != nil && dnode.right != nil {
 		pred := dnode.left.findMax()
 		dnode.elem = pred.elem
+		T.updatePath(dnode)
 		dnode = pred
 	}
 
@@ -529,6 +604,9 @@ func (T *RedBlackTree) delete(E Elem) {
 		T.deleteCase1(dnode)
 	}
 	T.replaceNode(dnode, child)
+	if dnode.parent != nil {
+		T.updatePath(dnode.parent)
+	}
 
 	if isRed(T.root) {
 		T.root.red = false
@@ -625,6 +703,90 @@ func (T *RedBlackTree) deleteCase6(dnode *node) {
 	}
 }
 
+// setRoot makes the given node the root of the Tree
+// and recalculates the size.
+func (T *RedBlackTree) setRoot(n *node) {
+	T.root = n
+	if n != nil {
+		n.parent = nil
+		n.red = false
+	}
+	T.size = sizeOf(n)
+}
+
+// blank returns an empty Tree with the same settings
+// as the Tree.
+func (T *RedBlackTree) blank() *RedBlackTree {
+	return &RedBlackTree{T.less, 0, nil, T.augment, T.elemType, nil}
+}
+
+// build creates a balanced subtree from sorted elements. Nodes on
+// the deepest level are red when the level is not the only one,
+// every other node is black.
+func (T *RedBlackTree) build(elems []Elem) *node {
+	depth := 0
+	for n := len(elems); n > 1; n /= 2 {
+		depth++
+	}
+	return T.buildLevel(elems, 0, depth)
+}
+
+// buildLevel is the recursive part of build.
+func (T *RedBlackTree) buildLevel(elems []Elem, level, depth int) *node {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	mid := len(elems) / 2
+	n := &node{elems[mid], nil, nil, nil, level == depth && depth > 0, 1, nil}
+
+	n.left = T.buildLevel(elems[:mid], level+1, depth)
+	n.right = T.buildLevel(elems[mid+1:], level+1, depth)
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+	T.update(n)
+
+	return n
+}
+
+// sizeOf returns the number of nodes in the subtree. The
+// leafs (nil) are considered empty.
+func sizeOf(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// augOf returns the augmented value of the subtree. The
+// leafs (nil) have no value.
+func augOf(n *node) interface{} {
+	if n == nil {
+		return nil
+	}
+	return n.aug
+}
+
+// update recalculates the size and the augmented value of
+// a node from its children.
+func (T *RedBlackTree) update(n *node) {
+	n.size = sizeOf(n.left) + sizeOf(n.right) + 1
+	if T.augment != nil {
+		n.aug = T.augment(n.elem, augOf(n.left), augOf(n.right))
+	}
+}
+
+// updatePath updates the node and all of its ancestors.
+func (T *RedBlackTree) updatePath(n *node) {
+	for ; n != nil; n = n.parent {
+		T.update(n)
+	}
+}
+
 // findMax returns the rightmost (biggest) node in
 // the subtree.
 func (N *node) findMax() *node {
@@ -645,6 +807,19 @@ func (N *node) findMin() *node {
 	return found
 }
 
+// next returns the in-order successor of the node, or nil
+// if the node holds the biggest element.
+func (N *node) next() *node {
+	if N.right != nil {
+		return N.right.findMin()
+	}
+	n := N
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
 // uncle returns the parent's sibling().
 func (N *node) uncle() *node {
 	if N.parent == nil {
@@ -673,21 +848,3 @@ func (N *node) sibling() *node {
 	}
 	return nil
 }
-
-// print is used with debugging. It prints a simple tree
-// representation.
-func print(N *node, padding int) {
-	if N != nil {
-		newp := padding + 5
-		print(N.right, newp)
-		for i := 0; i < padding; i++ {
-			fmt.Print("-")
-		}
-		if N.red {
-			fmt.Printf("(%d) \n", N.elem)
-		} else {
-			fmt.Printf("|%d| \n", N.elem)
-		}
-		print(N.left, newp)
-	}
-}
@@ -6,6 +6,63 @@ func intLess(a, b interface{}) bool {
 	return a.(int) < b.(int)
 }
 
+// checkTree reports every broken redblacktree invariant.
+func checkTree(t *testing.T, tree *RedBlackTree) {
+	if isRed(tree.root) {
+		t.Errorf("The root should be black.")
+	}
+	if tree.root != nil && tree.root.parent != nil {
+		t.Errorf("The root should not have a parent.")
+	}
+	if sizeOf(tree.root) != tree.size {
+		t.Errorf("Size is %d but the tree holds %d nodes.", tree.size, sizeOf(tree.root))
+	}
+	checkNode(t, tree, tree.root)
+}
+
+// checkNode checks the subtree and returns its black height.
+func checkNode(t *testing.T, tree *RedBlackTree, n *node) int {
+	if n == nil {
+		return 0
+	}
+	for _, c := range []*node{n.left, n.right} {
+		if c != nil && c.parent != n {
+			t.Errorf("Broken parent pointer below %v.", n.elem)
+		}
+		if n.red && isRed(c) {
+			t.Errorf("Red node %v has a red child.", n.elem)
+		}
+	}
+	if n.left != nil && !tree.less(n.left.elem, n.elem) {
+		t.Errorf("Left child of %v is out of order.", n.elem)
+	}
+	if n.right != nil && !tree.less(n.elem, n.right.elem) {
+		t.Errorf("Right child of %v is out of order.", n.elem)
+	}
+	if n.size != sizeOf(n.left)+sizeOf(n.right)+1 {
+		t.Errorf("Wrong size at %v.", n.elem)
+	}
+
+	lh := checkNode(t, tree, n.left)
+	rh := checkNode(t, tree, n.right)
+	if lh != rh {
+		t.Errorf("Black heights differ below %v.", n.elem)
+	}
+	if !n.red {
+		lh++
+	}
+	return lh
+}
+
+// toSlice returns the elements of the tree in order.
+func toSlice(tree *RedBlackTree) []int {
+	res := []int{}
+	for x := range tree.InOrder() {
+		res = append(res, x.(int))
+	}
+	return res
+}
+
 func TestNew(t *testing.T) {
 	tree := New(intLess)
 
@@ -14,6 +71,54 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestFromSorted(t *testing.T) {
+	for n := 0; n < 70; n++ {
+		slc := make([]int, n)
+		for i := range slc {
+			slc[i] = i * 2
+		}
+
+		tree, err := FromSorted(intLess, slc)
+		if err != nil {
+			t.Fatalf("FromSorted should accept a sorted slice.")
+		}
+		checkTree(t, tree)
+
+		if tree.Size() != n || len(toSlice(tree)) != n {
+			t.Errorf("FromSorted should keep every element.")
+		}
+	}
+
+	if _, err := FromSorted(intLess, []int{1, 3, 2}); err == nil {
+		t.Errorf("FromSorted should refuse an unsorted slice.")
+	}
+
+	if _, err := FromSorted(intLess, []int{1, 2, 2}); err == nil {
+		t.Errorf("FromSorted should refuse duplicates.")
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	tree := FromSlice(intLess, []int{5, 3, 9, 3, 1, 7})
+	checkTree(t, tree)
+
+	res := toSlice(tree)
+	exp := []int{1, 3, 5, 7, 9}
+
+	if len(res) != len(exp) {
+		t.Fatalf("FromSlice should drop duplicates.")
+	}
+	for i := range exp {
+		if res[i] != exp[i] {
+			t.Errorf("FromSlice should sort the elements.")
+		}
+	}
+
+	tree.Add(4)
+	tree.Remove(5)
+	checkTree(t, tree)
+}
+
 func TestSize(t *testing.T) {
 	tree := New(intLess)
 
@@ -73,6 +178,18 @@ func TestNRemove(t *testing.T) {
 	}
 }
 
+func TestRemoveInvariants(t *testing.T) {
+	tree := New(intLess)
+
+	for x := 0; x < 100; x++ {
+		tree.Add((x * 37) % 100)
+	}
+	for x := 0; x < 100; x += 3 {
+		tree.Remove((x * 11) % 100)
+	}
+	checkTree(t, tree)
+}
+
 func TestContains(t *testing.T) {
 	tree := New(intLess)
 	tree.Add(10)
@@ -86,6 +203,15 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestGet(t *testing.T) {
+	tree := New(intLess)
+	tree.Add(10)
+
+	if tree.Get(10) != 10 || tree.Get(20) != nil {
+		t.Errorf("Get should return the equal element, or nil.")
+	}
+}
+
 func TestFirst(t *testing.T) {
 	tree := New(intLess)
 
@@ -0,0 +1,174 @@
+package redblacktree
+
+// The set operations below modify the receiving Tree and leave the
+// other Tree untouched. Both Trees must use the same LessFunc.
+//
+// They are built on split and join: the receiving Tree is split around
+// the root of the other Tree and the halves are handled recursively.
+// Merging a Tree of size m into a Tree of size n takes
+// O(m log(n/m + 1)) time.
+
+// Union adds every element of the other Tree to the Tree.
+// Where both Trees hold an equal element, the Tree keeps its own.
+//
+// e.g. (2 (1) ()).Union((3 (2) ())) => (2 (1) (3))
+//
+func (T *RedBlackTree) Union(other *RedBlackTree) {
+	size := T.size
+	root, _ := T.union(T.root, blackHeight(T.root), other.root)
+	T.setRoot(root)
+	if T.size != size {
+		T.notify(OpReset, nil)
+	}
+}
+
+// Intersection removes every element from the Tree that isn't
+// found in the other Tree.
+//
+// e.g. (2 (1) ()).Intersection((3 (2) ())) => (2 () ())
+//
+func (T *RedBlackTree) Intersection(other *RedBlackTree) {
+	size := T.size
+	root, _ := T.intersection(T.root, blackHeight(T.root), other.root)
+	T.setRoot(root)
+	if T.size != size {
+		T.notify(OpReset, nil)
+	}
+}
+
+// Difference removes every element from the Tree that is
+// found in the other Tree.
+//
+// e.g. (2 (1) ()).Difference((3 (2) ())) => (1 () ())
+//
+func (T *RedBlackTree) Difference(other *RedBlackTree) {
+	size := T.size
+	root, _ := T.difference(T.root, blackHeight(T.root), other.root)
+	T.setRoot(root)
+	if T.size != size {
+		T.notify(OpReset, nil)
+	}
+}
+
+// SymmetricDifference leaves the Tree with the elements that are
+// found in exactly one of the two Trees.
+//
+// e.g. (2 (1) ()).SymmetricDifference((3 (2) ())) => (3 (1) ())
+//
+func (T *RedBlackTree) SymmetricDifference(other *RedBlackTree) {
+	root, _ := T.symmetricDifference(T.root, blackHeight(T.root), other.root)
+	T.setRoot(root)
+	if !other.Empty() {
+		T.notify(OpReset, nil)
+	}
+}
+
+// IsSubset returns true if every element of the Tree is
+// found in the other Tree.
+//
+// e.g. (2 (1) ()).IsSubset((2 (1) (3))) => true
+//
+func (T *RedBlackTree) IsSubset(other *RedBlackTree) bool {
+	if T.size > other.size {
+		return false
+	}
+	if T.Empty() {
+		return true
+	}
+	for n := T.root.findMin(); n != nil; n = n.next() {
+		if other.get(n.elem) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if both Trees hold the same elements.
+//
+// e.g. (2 (1) (3)).Equal((1 () (2 () (3)))) => true
+//
+func (T *RedBlackTree) Equal(other *RedBlackTree) bool {
+	if T.size != other.size {
+		return false
+	}
+	if T.Empty() {
+		return true
+	}
+	a, b := T.root.findMin(), other.root.findMin()
+	for a != nil {
+		if T.less(a.elem, b.elem) || T.less(b.elem, a.elem) {
+			return false
+		}
+		a, b = a.next(), b.next()
+	}
+	return true
+}
+
+// union is the recursive part of Union.
+func (T *RedBlackTree) union(n *node, h int, o *node) (*node, int) {
+	if o == nil {
+		return n, h
+	}
+	if n == nil {
+		c := T.clone(o)
+		return c, blackHeight(c)
+	}
+
+	l, lh, found, r, rh := T.split(n, h, o.elem)
+	l, lh = T.union(l, lh, o.left)
+	r, rh = T.union(r, rh, o.right)
+
+	if found == nil {
+		found = &node{o.elem, nil, nil, nil, false, 1, nil}
+	}
+	return T.join(l, lh, found, r, rh)
+}
+
+// intersection is the recursive part of Intersection.
+func (T *RedBlackTree) intersection(n *node, h int, o *node) (*node, int) {
+	if n == nil || o == nil {
+		return nil, 0
+	}
+
+	l, lh, found, r, rh := T.split(n, h, o.elem)
+	l, lh = T.intersection(l, lh, o.left)
+	r, rh = T.intersection(r, rh, o.right)
+
+	if found == nil {
+		return T.join2(l, lh, r, rh)
+	}
+	return T.join(l, lh, found, r, rh)
+}
+
+// difference is the recursive part of Difference.
+func (T *RedBlackTree) difference(n *node, h int, o *node) (*node, int) {
+	if n == nil || o == nil {
+		return n, h
+	}
+
+	l, lh, _, r, rh := T.split(n, h, o.elem)
+	l, lh = T.difference(l, lh, o.left)
+	r, rh = T.difference(r, rh, o.right)
+
+	return T.join2(l, lh, r, rh)
+}
+
+// symmetricDifference is the recursive part of SymmetricDifference.
+func (T *RedBlackTree) symmetricDifference(n *node, h int, o *node) (*node, int) {
+	if o == nil {
+		return n, h
+	}
+	if n == nil {
+		c := T.clone(o)
+		return c, blackHeight(c)
+	}
+
+	l, lh, found, r, rh := T.split(n, h, o.elem)
+	l, lh = T.symmetricDifference(l, lh, o.left)
+	r, rh = T.symmetricDifference(r, rh, o.right)
+
+	if found != nil {
+		return T.join2(l, lh, r, rh)
+	}
+	return T.join(l, lh, &node{o.elem, nil, nil, nil, false, 1, nil}, r, rh)
+}
@@ -0,0 +1,131 @@
+package redblacktree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomTree returns a tree with n random elements below max, and
+// the same elements as a map.
+func randomTree(r *rand.Rand, n, max int) (*RedBlackTree, map[int]bool) {
+	tree := New(intLess)
+	set := map[int]bool{}
+	for i := 0; i < n; i++ {
+		x := r.Intn(max)
+		tree.Add(x)
+		set[x] = true
+	}
+	return tree, set
+}
+
+// sameElements returns true if the tree holds exactly the
+// elements in the map.
+func sameElements(tree *RedBlackTree, set map[int]bool) bool {
+	res := toSlice(tree)
+	if len(res) != len(set) {
+		return false
+	}
+	for _, x := range res {
+		if !set[x] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		a, as := randomTree(r, r.Intn(200), 300)
+		b, bs := randomTree(r, r.Intn(200), 300)
+
+		union, inter, diff, sym := map[int]bool{}, map[int]bool{}, map[int]bool{}, map[int]bool{}
+		for x := range as {
+			union[x] = true
+			if bs[x] {
+				inter[x] = true
+			} else {
+				diff[x] = true
+				sym[x] = true
+			}
+		}
+		for x := range bs {
+			union[x] = true
+			if !as[x] {
+				sym[x] = true
+			}
+		}
+
+		u := FromSlice(intLess, toSlice(a))
+		u.Union(b)
+		checkTree(t, u)
+		if !sameElements(u, union) {
+			t.Errorf("Union should hold the elements of both trees.")
+		}
+
+		n := FromSlice(intLess, toSlice(a))
+		n.Intersection(b)
+		checkTree(t, n)
+		if !sameElements(n, inter) {
+			t.Errorf("Intersection should hold the common elements.")
+		}
+
+		d := FromSlice(intLess, toSlice(a))
+		d.Difference(b)
+		checkTree(t, d)
+		if !sameElements(d, diff) {
+			t.Errorf("Difference should hold the elements only found in the tree.")
+		}
+
+		s := FromSlice(intLess, toSlice(a))
+		s.SymmetricDifference(b)
+		checkTree(t, s)
+		if !sameElements(s, sym) {
+			t.Errorf("SymmetricDifference should hold the elements found in one tree.")
+		}
+
+		checkTree(t, b)
+		if !sameElements(b, bs) {
+			t.Errorf("The other tree should be left untouched.")
+		}
+	}
+}
+
+func TestIsSubset(t *testing.T) {
+	a := FromSlice(intLess, []int{2, 4})
+	b := FromSlice(intLess, []int{1, 2, 3, 4})
+
+	if !a.IsSubset(b) {
+		t.Errorf("(2,4) is a subset of (1,2,3,4).")
+	}
+	if b.IsSubset(a) {
+		t.Errorf("(1,2,3,4) is not a subset of (2,4).")
+	}
+	if !New(intLess).IsSubset(a) {
+		t.Errorf("The empty tree is a subset of every tree.")
+	}
+
+	a.Add(5)
+	if a.IsSubset(b) {
+		t.Errorf("(2,4,5) is not a subset of (1,2,3,4).")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := FromSlice(intLess, []int{3, 1, 2})
+	b := New(intLess)
+	b.Add(1)
+	b.Add(2)
+	b.Add(3)
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("Trees with the same elements should be equal.")
+	}
+
+	b.Remove(2)
+	b.Add(4)
+	if a.Equal(b) {
+		t.Errorf("Trees with different elements should not be equal.")
+	}
+}
@@ -0,0 +1,247 @@
+package redblacktree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// A snapshot starts with a magic string, a version byte and the number
+// of elements as a big endian uint64. The elements follow in order,
+// each as a uvarint length and the bytes of its Codec. It ends with a
+// big endian CRC-32C of everything before it.
+//
+// e.g. [GRBS][1][3][len][1][len][2][len][3][crc]
+//
+const (
+	snapshotMagic   = "GRBS"
+	snapshotVersion = 1
+)
+
+// Codec encodes and decodes the elements of a snapshot.
+type Codec interface {
+	Encode(E Elem) ([]byte, error)
+	Decode(data []byte) (Elem, error)
+}
+
+// GobCodec is a Codec using encoding/gob. It is used when no Codec is
+// given. Elements of user defined types must be registered with
+// gob.Register.
+type GobCodec struct{}
+
+// Encode encodes the element with encoding/gob.
+func (GobCodec) Encode(E Elem) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&E)
+	return buf.Bytes(), err
+}
+
+// Decode decodes an element encoded by Encode.
+func (GobCodec) Decode(data []byte) (Elem, error) {
+	var E Elem
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&E)
+	return E, err
+}
+
+// SaveSnapshot writes the elements of the Tree to the file at path,
+// encoded with the given Codec, or GobCodec if it is nil. The snapshot
+// is written to a temporary file which replaces the old one only when
+// it is complete and on disk, so a crash leaves one or the other.
+//
+// e.g. err := mytree.SaveSnapshot("/var/lib/index.snap", nil)
+//
+func (T *RedBlackTree) SaveSnapshot(path string, codec Codec) (err error) {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	w := bufio.NewWriter(io.MultiWriter(f, crc))
+
+	var buf [binary.MaxVarintLen64]byte
+	w.WriteString(snapshotMagic)
+	w.WriteByte(snapshotVersion)
+	binary.BigEndian.PutUint64(buf[:], uint64(T.size))
+	w.Write(buf[:8])
+
+	inOrder(T.root, func(n *node) {
+		if err != nil {
+			return
+		}
+		var data []byte
+		if data, err = codec.Encode(n.elem); err != nil {
+			return
+		}
+		w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(data)))])
+		w.Write(data)
+	})
+	if err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(buf[:], crc.Sum32())
+	if _, err = f.Write(buf[:4]); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// LoadSnapshot reads a Tree from a snapshot written by SaveSnapshot,
+// decoding the elements with the given Codec, or GobCodec if it is nil.
+// The whole snapshot is checked before the Tree is built in linear
+// time, so a damaged snapshot returns an error and never a partial Tree.
+//
+// e.g. mytree, err := redblacktree.LoadSnapshot("/var/lib/index.snap", intLess, nil)
+//
+func LoadSnapshot(path string, lf LessFunc, codec Codec) (*RedBlackTree, error) {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	r := &snapshotReader{bufio.NewReader(f), crc, info.Size()}
+
+	head := make([]byte, len(snapshotMagic)+1+8)
+	if err := r.read(head); err != nil {
+		return nil, err
+	}
+	if string(head[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, errors.New("File is not a Tree snapshot.")
+	}
+	if v := head[len(snapshotMagic)]; v != snapshotVersion {
+		return nil, fmt.Errorf("Unsupported Tree snapshot version %d.", v)
+	}
+
+	count := binary.BigEndian.Uint64(head[len(snapshotMagic)+1:])
+	if count > uint64(r.left) {
+		return nil, errors.New("Corrupt Tree snapshot: size mismatch.")
+	}
+
+	elems := make([]Elem, count)
+	for i := range elems {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, truncated(err)
+		}
+		if n > uint64(r.left) {
+			return nil, errors.New("Tree snapshot is truncated.")
+		}
+
+		data := make([]byte, n)
+		if err := r.read(data); err != nil {
+			return nil, err
+		}
+		if elems[i], err = codec.Decode(data); err != nil {
+			return nil, fmt.Errorf("Corrupt Tree snapshot: %v", err)
+		}
+	}
+
+	sum := crc.Sum32()
+	var trailer [4]byte
+	if _, err := io.ReadFull(r.r, trailer[:]); err != nil {
+		return nil, truncated(err)
+	}
+	if binary.BigEndian.Uint32(trailer[:]) != sum {
+		return nil, errors.New("Corrupt Tree snapshot: checksum mismatch.")
+	}
+	if _, err := r.r.ReadByte(); err != io.EOF {
+		return nil, errors.New("Corrupt Tree snapshot: data after the checksum.")
+	}
+
+	T := New(lf)
+	if err := T.restore(elems); err != nil {
+		return nil, err
+	}
+	return T, nil
+}
+
+// snapshotReader reads a snapshot, adding what it reads to the
+// checksum and counting the bytes left in the file.
+type snapshotReader struct {
+	r    *bufio.Reader
+	crc  hash.Hash32
+	left int64
+}
+
+// ReadByte implements io.ByteReader, for binary.ReadUvarint.
+func (R *snapshotReader) ReadByte() (byte, error) {
+	b, err := R.r.ReadByte()
+	if err == nil {
+		R.crc.Write([]byte{b})
+		R.left--
+	}
+	return b, err
+}
+
+// read fills buf, or returns an error if the snapshot ends.
+func (R *snapshotReader) read(buf []byte) error {
+	if _, err := io.ReadFull(R.r, buf); err != nil {
+		return truncated(err)
+	}
+	R.crc.Write(buf)
+	R.left -= int64(len(buf))
+	return nil
+}
+
+// truncated turns the errors of a snapshot that ends too soon into
+// a clear one.
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("Tree snapshot is truncated.")
+	}
+	return err
+}
+
+// syncDir flushes a directory to disk, so a file
+// renamed in it survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
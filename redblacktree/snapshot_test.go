@@ -0,0 +1,108 @@
+package redblacktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// intCodec encodes ints as decimal strings.
+type intCodec struct{}
+
+func (intCodec) Encode(E Elem) ([]byte, error) {
+	return []byte(strconv.Itoa(E.(int))), nil
+}
+
+func (intCodec) Decode(data []byte) (Elem, error) {
+	return strconv.Atoi(string(data))
+}
+
+// failCodec fails to encode any element.
+type failCodec struct{ intCodec }
+
+func (failCodec) Encode(E Elem) ([]byte, error) {
+	return nil, errors.New("Nope.")
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.snap")
+
+	for _, codec := range []Codec{nil, intCodec{}} {
+		for _, n := range []int{0, 1, 2, 100, 1000} {
+			tree := New(intLess)
+			for x := 0; x < n; x++ {
+				tree.Add((x * 7) % n)
+			}
+
+			if err := tree.SaveSnapshot(path, codec); err != nil {
+				t.Fatalf("SaveSnapshot failed: %v", err)
+			}
+			res, err := LoadSnapshot(path, intLess, codec)
+			if err != nil {
+				t.Fatalf("LoadSnapshot failed: %v", err)
+			}
+			checkTree(t, res)
+
+			if !res.Equal(tree) || res.Size() != n {
+				t.Errorf("LoadSnapshot should restore every element.")
+			}
+		}
+	}
+}
+
+func TestSnapshotAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tree.snap")
+
+	FromSlice(intLess, []int{1, 2, 3}).SaveSnapshot(path, intCodec{})
+	if FromSlice(intLess, []int{4, 5}).SaveSnapshot(path, failCodec{}) == nil {
+		t.Errorf("SaveSnapshot should return the error of the Codec.")
+	}
+
+	res, err := LoadSnapshot(path, intLess, intCodec{})
+	if err != nil || res.Size() != 3 {
+		t.Errorf("A failed SaveSnapshot should leave the old snapshot.")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("SaveSnapshot should not leave temporary files behind.")
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.snap")
+	FromSlice(intLess, []int{1, 22, 333, 4444}).SaveSnapshot(path, intCodec{})
+	data, _ := os.ReadFile(path)
+
+	if _, err := LoadSnapshot(path+".missing", intLess, intCodec{}); err == nil {
+		t.Errorf("LoadSnapshot should fail on a missing file.")
+	}
+
+	for n := 0; n < len(data); n++ {
+		os.WriteFile(path, data[:n], 0644)
+		if _, err := LoadSnapshot(path, intLess, intCodec{}); err == nil {
+			t.Errorf("LoadSnapshot should refuse a snapshot cut at %d.", n)
+		}
+	}
+
+	for i := range data {
+		bad := append([]byte{}, data...)
+		bad[i] ^= 0x10
+		os.WriteFile(path, bad, 0644)
+		if _, err := LoadSnapshot(path, intLess, intCodec{}); err == nil {
+			t.Errorf("LoadSnapshot should refuse a snapshot with byte %d flipped.", i)
+		}
+	}
+
+	os.WriteFile(path, append(data, 0), 0644)
+	if _, err := LoadSnapshot(path, intLess, intCodec{}); err == nil {
+		t.Errorf("LoadSnapshot should refuse data after the checksum.")
+	}
+
+	os.WriteFile(path, data, 0644)
+	greater := func(a, b interface{}) bool { return a.(int) > b.(int) }
+	if _, err := LoadSnapshot(path, greater, intCodec{}); err == nil {
+		t.Errorf("LoadSnapshot should refuse elements out of order.")
+	}
+}
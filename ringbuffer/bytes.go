@@ -0,0 +1,151 @@
+package ringbuffer
+
+import (
+	"io"
+	"sync"
+)
+
+// Bytes is a ring buffer of bytes. It implements io.Reader and
+// io.Writer, and uses the same policies and lock as RingBuffer.
+//
+// e.g. tail := ringbuffer.NewBytes(4096, ringbuffer.Overwrite)
+//      log.SetOutput(tail)
+//
+type Bytes struct {
+	buf    []byte
+	head   int
+	size   int
+	policy Policy
+	mu     sync.RWMutex
+}
+
+// NewBytes is used as a constructor for the Bytes struct.
+// A capacity below 1 is raised to 1.
+//
+// e.g. mybuffer := ringbuffer.NewBytes(4096, ringbuffer.Reject)
+//
+func NewBytes(capacity int, policy Policy) *Bytes {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Bytes{buf: make([]byte, capacity), policy: policy}
+}
+
+// Len returns the number of unread bytes.
+func (B *Bytes) Len() int {
+	B.mu.RLock()
+	defer B.mu.RUnlock()
+
+	return B.size
+}
+
+// Cap returns the capacity of the buffer.
+func (B *Bytes) Cap() int {
+	return len(B.buf)
+}
+
+// Write adds the bytes after the newest ones. When they don't fit,
+// the Overwrite policy drops the oldest bytes, and keeps only the end
+// of p if p itself is too long. The Reject policy writes what fits
+// and returns ErrFull. It implements io.Writer.
+//
+// e.g. cap 4 "abc".Write("de") => "bcde" with Overwrite
+//      cap 4 "abc".Write("de") => "abcd", 1, ErrFull with Reject
+//
+func (B *Bytes) Write(p []byte) (int, error) {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	n := len(p)
+	if free := len(B.buf) - B.size; n > free {
+		if B.policy == Reject {
+			B.write(p[:free])
+			return free, ErrFull
+		}
+
+		if n > len(B.buf) {
+			p = p[n-len(B.buf):]
+		}
+		drop := len(p) - free
+		B.head = (B.head + drop) % len(B.buf)
+		B.size -= drop
+	}
+
+	B.write(p)
+	return n, nil
+}
+
+// Read moves the oldest bytes into p. It returns io.EOF when the
+// buffer is empty. It implements io.Reader.
+//
+// e.g. "abcd".Read(p[:3]) => 3, p = "abc"
+//
+func (B *Bytes) Read(p []byte) (int, error) {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	if B.size == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	n := B.peek(p)
+	B.head = (B.head + n) % len(B.buf)
+	B.size -= n
+	return n, nil
+}
+
+// Peek copies the oldest bytes into p without removing them,
+// and returns the number of bytes copied.
+//
+// e.g. "abcd".Peek(p[:3]) => 3, p = "abc"
+//
+func (B *Bytes) Peek(p []byte) int {
+	B.mu.RLock()
+	defer B.mu.RUnlock()
+
+	return B.peek(p)
+}
+
+// Bytes returns a snapshot of the unread bytes, oldest first.
+//
+// e.g. "abcd".Bytes() => "abcd"
+//
+func (B *Bytes) Bytes() []byte {
+	B.mu.RLock()
+	defer B.mu.RUnlock()
+
+	res := make([]byte, B.size)
+	B.peek(res)
+	return res
+}
+
+// Reset removes every byte from the buffer.
+func (B *Bytes) Reset() {
+	B.mu.Lock()
+	defer B.mu.Unlock()
+
+	B.head, B.size = 0, 0
+}
+
+// write copies p after the newest bytes. It must fit.
+func (B *Bytes) write(p []byte) {
+	tail := (B.head + B.size) % len(B.buf)
+	n := copy(B.buf[tail:], p)
+	copy(B.buf, p[n:])
+	B.size += len(p)
+}
+
+// peek copies the oldest bytes into p and returns how many.
+func (B *Bytes) peek(p []byte) int {
+	n := len(p)
+	if n > B.size {
+		n = B.size
+	}
+
+	first := copy(p[:n], B.buf[B.head:])
+	copy(p[first:n], B.buf)
+	return n
+}
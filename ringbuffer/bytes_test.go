@@ -0,0 +1,73 @@
+package ringbuffer
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestBytesOverwrite(t *testing.T) {
+	b := NewBytes(4, Overwrite)
+	b.Write([]byte("abc"))
+
+	if n, err := b.Write([]byte("de")); n != 2 || err != nil {
+		t.Errorf("Write should never fail with Overwrite.")
+	}
+	if string(b.Bytes()) != "bcde" || b.Len() != 4 || b.Cap() != 4 {
+		t.Errorf("Write should drop the oldest bytes, got %q.", b.Bytes())
+	}
+
+	if n, _ := b.Write([]byte("0123456789")); n != 10 || string(b.Bytes()) != "6789" {
+		t.Errorf("Write should keep the end of a long write, got %q.", b.Bytes())
+	}
+}
+
+func TestBytesReject(t *testing.T) {
+	b := NewBytes(4, Reject)
+	b.Write([]byte("abc"))
+
+	if n, err := b.Write([]byte("de")); n != 1 || err != ErrFull {
+		t.Errorf("Write should write what fits and return ErrFull.")
+	}
+	if string(b.Bytes()) != "abcd" {
+		t.Errorf("Write should keep the old bytes, got %q.", b.Bytes())
+	}
+}
+
+func TestBytesRead(t *testing.T) {
+	b := NewBytes(4, Overwrite)
+	b.Write([]byte("abcdef"))
+
+	p := make([]byte, 3)
+	if n := b.Peek(p); n != 3 || string(p) != "cde" || b.Len() != 4 {
+		t.Errorf("Peek should not remove bytes.")
+	}
+	if n, err := b.Read(p); n != 3 || err != nil || string(p) != "cde" {
+		t.Errorf("Read should return the oldest bytes.")
+	}
+
+	b.Write([]byte("gh"))
+	if n, _ := b.Read(p); n != 3 || string(p) != "fgh" {
+		t.Errorf("Read should wrap around, got %q.", p[:n])
+	}
+	if _, err := b.Read(p); err != io.EOF {
+		t.Errorf("Read of an empty buffer should return io.EOF.")
+	}
+
+	b.Write([]byte("xy"))
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Reset should empty the buffer.")
+	}
+}
+
+func TestBytesIO(t *testing.T) {
+	b := NewBytes(16, Overwrite)
+	io.Copy(b, strings.NewReader("the last sixteen bytes remain"))
+
+	out, err := ioutil.ReadAll(b)
+	if err != nil || string(out) != "een bytes remain" {
+		t.Errorf("Bytes should work as an io.Reader and io.Writer, got %q.", out)
+	}
+}
@@ -0,0 +1,185 @@
+// Package ringbuffer provides fixed-capacity first-in-first-out
+// buffers, which either overwrite their oldest element or refuse
+// new ones when full. It is thread-safe.
+package ringbuffer
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned when writing to a full buffer with the
+// Reject policy.
+var ErrFull = errors.New("Buffer is full.")
+
+// Policy decides what a full buffer does with a write.
+type Policy int
+
+const (
+	Overwrite Policy = iota // drop the oldest element to make room
+	Reject                  // refuse the write with ErrFull
+)
+
+// A ringbuffer has a fixed slice of elements, the position of the
+// oldest element, the number of elements, a policy and a lock.
+//
+// The elements run from the oldest to the end of the slice, and go on
+// from its start.
+//
+// e.g. capacity 5 after writing 1 to 7 with Overwrite:
+//     [6 7 3 4 5]
+//          ^ oldest
+//
+type RingBuffer struct {
+	elems  []Elem
+	head   int
+	size   int
+	policy Policy
+	mu     sync.RWMutex
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// New is used as a constructor for the RingBuffer struct.
+// A capacity below 1 is raised to 1.
+//
+// e.g. mybuffer := ringbuffer.New(100, ringbuffer.Overwrite)
+//
+func New(capacity int, policy Policy) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer{elems: make([]Elem, capacity), policy: policy}
+}
+
+// Size returns the number of elements in the buffer.
+//
+// e.g. (1,2,3).Size() => 3
+//
+func (R *RingBuffer) Size() int {
+	R.mu.RLock()
+	defer R.mu.RUnlock()
+
+	return R.size
+}
+
+// Len is an alias for Size().
+func (R *RingBuffer) Len() int {
+	return R.Size()
+}
+
+// Cap returns the capacity of the buffer.
+//
+// e.g. New(5, Reject).Cap() => 5
+//
+func (R *RingBuffer) Cap() int {
+	return len(R.elems)
+}
+
+// Empty returns true if the buffer is empty.
+//
+// e.g. ().Empty() => true
+//
+func (R *RingBuffer) Empty() bool {
+	return R.Size() == 0
+}
+
+// Full returns true if the buffer holds as many
+// elements as it can.
+//
+// e.g. cap 3 (1,2,3).Full() => true
+//
+func (R *RingBuffer) Full() bool {
+	return R.Size() == R.Cap()
+}
+
+// Write adds an element after the newest one. When the buffer is
+// full, the Overwrite policy drops the oldest element, and the
+// Reject policy returns ErrFull.
+//
+// e.g. cap 3 (1,2,3).Write(4) => (2,3,4) with Overwrite
+//
+func (R *RingBuffer) Write(V Elem) error {
+	R.mu.Lock()
+	defer R.mu.Unlock()
+
+	if R.size == len(R.elems) {
+		if R.policy == Reject {
+			return ErrFull
+		}
+		R.elems[R.head] = V
+		R.head = (R.head + 1) % len(R.elems)
+		return nil
+	}
+
+	R.elems[(R.head+R.size)%len(R.elems)] = V
+	R.size++
+	return nil
+}
+
+// Read returns the oldest element and removes it.
+//
+// e.g. (1,2,3).Read() => 1
+//       --^-- .Read() => 2
+//
+func (R *RingBuffer) Read() Elem {
+	R.mu.Lock()
+	defer R.mu.Unlock()
+
+	if R.size == 0 {
+		return nil
+	}
+
+	V := R.elems[R.head]
+	R.elems[R.head] = nil
+	R.head = (R.head + 1) % len(R.elems)
+	R.size--
+	return V
+}
+
+// Peek returns the oldest element without removing it.
+//
+// e.g. (1,2,3).Peek() => 1
+//       --^-- .Peek() => 1
+//
+func (R *RingBuffer) Peek() Elem {
+	R.mu.RLock()
+	defer R.mu.RUnlock()
+
+	if R.size == 0 {
+		return nil
+	}
+	return R.elems[R.head]
+}
+
+// Iter returns an iterator over a snapshot of the buffer, from the
+// oldest element to the newest. Later writes don't affect it.
+//
+// e.g. for x := range (1,2,3).Iter() { x } => 1, 2, 3
+//
+func (R *RingBuffer) Iter() chan Elem {
+	R.mu.RLock()
+	defer R.mu.RUnlock()
+
+	ch := make(chan Elem, R.size)
+	for i := 0; i < R.size; i++ {
+		ch <- R.elems[(R.head+i)%len(R.elems)]
+	}
+	close(ch)
+	return ch
+}
+
+// Clear removes every element from the buffer.
+//
+// e.g. (1,2,3).Clear() => ()
+//
+func (R *RingBuffer) Clear() {
+	R.mu.Lock()
+	defer R.mu.Unlock()
+
+	for i := range R.elems {
+		R.elems[i] = nil
+	}
+	R.head, R.size = 0, 0
+}
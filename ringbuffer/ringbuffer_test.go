@@ -0,0 +1,123 @@
+package ringbuffer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// collect returns the elements of the iterator as a string.
+func collect(ch chan Elem) string {
+	res := []Elem{}
+	for x := range ch {
+		res = append(res, x)
+	}
+	return fmt.Sprint(res)
+}
+
+func TestNew(t *testing.T) {
+	r := New(3, Reject)
+
+	if r.Size() != 0 || !r.Empty() || r.Full() || r.Cap() != 3 || r.Read() != nil || r.Peek() != nil {
+		t.Errorf("New constructor is broken.")
+	}
+	if New(0, Reject).Cap() != 1 {
+		t.Errorf("New should raise a small capacity to 1.")
+	}
+}
+
+func TestOverwrite(t *testing.T) {
+	r := New(3, Overwrite)
+	for i := 1; i <= 7; i++ {
+		if r.Write(i) != nil {
+			t.Errorf("Write should never fail with Overwrite.")
+		}
+	}
+
+	if r.Len() != 3 || !r.Full() || collect(r.Iter()) != "[5 6 7]" {
+		t.Errorf("Write should drop the oldest elements, got %v.", collect(r.Iter()))
+	}
+	if r.Peek() != 5 || r.Read() != 5 || r.Read() != 6 || r.Len() != 1 {
+		t.Errorf("Read should return the oldest element.")
+	}
+
+	r.Write(8)
+	r.Write(9)
+	if collect(r.Iter()) != "[7 8 9]" {
+		t.Errorf("Write should wrap around, got %v.", collect(r.Iter()))
+	}
+}
+
+func TestReject(t *testing.T) {
+	r := New(2, Reject)
+	r.Write(1)
+	r.Write(2)
+
+	if r.Write(3) != ErrFull {
+		t.Errorf("Write should return ErrFull when full.")
+	}
+	if collect(r.Iter()) != "[1 2]" {
+		t.Errorf("A rejected write should not change the buffer.")
+	}
+
+	r.Read()
+	if r.Write(3) != nil || collect(r.Iter()) != "[2 3]" {
+		t.Errorf("Write should succeed after a Read.")
+	}
+}
+
+func TestIterSnapshot(t *testing.T) {
+	r := New(3, Overwrite)
+	r.Write(1)
+	r.Write(2)
+
+	ch := r.Iter()
+	r.Write(3)
+	r.Write(4)
+
+	if collect(ch) != "[1 2]" {
+		t.Errorf("Iter should not see later writes.")
+	}
+}
+
+func TestClear(t *testing.T) {
+	r := New(3, Overwrite)
+	r.Write(1)
+	r.Write(2)
+	r.Clear()
+
+	if !r.Empty() || r.Write(3) != nil || collect(r.Iter()) != "[3]" {
+		t.Errorf("Clear should empty the buffer.")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	r := New(50, Reject)
+
+	var wg sync.WaitGroup
+	read := make([]int, 4)
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for r.Write(i) != nil {
+				}
+			}
+		}()
+		go func(g int) {
+			defer wg.Done()
+			for read[g] < 100 {
+				if r.Read() != nil {
+					read[g]++
+				}
+				r.Iter()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if !r.Empty() {
+		t.Errorf("Every written element should have been read.")
+	}
+}
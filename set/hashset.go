@@ -0,0 +1,218 @@
+package set
+
+import (
+	"errors"
+	"sync"
+)
+
+// A hashset keeps its elements as the keys of a go map, and has a
+// lock which is only used if the set was created with
+// NewConcurrentHashSet. The elements must be comparable.
+type HashSet struct {
+	items      map[Elem]struct{}
+	mu         sync.RWMutex
+	concurrent bool
+}
+
+// NewHashSet is used as a constructor for the HashSet
+// struct. The set is not thread-safe.
+//
+// e.g. myset := set.NewHashSet()
+//
+func NewHashSet() *HashSet {
+	return &HashSet{items: map[Elem]struct{}{}}
+}
+
+// NewConcurrentHashSet is used as a constructor for a thread-safe HashSet.
+//
+// e.g. myset := set.NewConcurrentHashSet()
+//
+func NewConcurrentHashSet() *HashSet {
+	return &HashSet{items: map[Elem]struct{}{}, concurrent: true}
+}
+
+// Size returns the size of the set.
+//
+// e.g. {1,2,3}.Size() => 3
+//
+func (H *HashSet) Size() int {
+	H.rlock()
+	defer H.runlock()
+
+	return len(H.items)
+}
+
+// Len is an alias for Size().
+func (H *HashSet) Len() int {
+	return H.Size()
+}
+
+// Empty returns true if the set is empty.
+//
+// e.g. {}.Empty() => true
+//
+func (H *HashSet) Empty() bool {
+	return H.Size() == 0
+}
+
+// Add puts an element in the set.
+//
+// e.g. {1,2}.Add(3) => {1,2,3}
+//
+func (H *HashSet) Add(E Elem) error {
+	H.lock()
+	defer H.unlock()
+
+	if _, ok := H.items[E]; ok {
+		return errors.New("Item already exists in Set.")
+	}
+	H.items[E] = struct{}{}
+	return nil
+}
+
+// Remove takes an element out of the set.
+//
+// e.g. {1,2,3}.Remove(2) => {1,3}
+//
+func (H *HashSet) Remove(E Elem) error {
+	H.lock()
+	defer H.unlock()
+
+	if _, ok := H.items[E]; !ok {
+		return errors.New("Item not found in Set.")
+	}
+	delete(H.items, E)
+	return nil
+}
+
+// Contains returns true if the given element exists
+// within the set.
+//
+// e.g. {1,2,3}.Contains(2) => true
+//
+func (H *HashSet) Contains(E Elem) bool {
+	H.rlock()
+	defer H.runlock()
+
+	_, ok := H.items[E]
+	return ok
+}
+
+// Iter returns an iterator over the elements of the set,
+// in no particular order.
+//
+// e.g. for x := range {1,2,3}.Iter() { x } => 2, 3, 1
+//
+func (H *HashSet) Iter() chan Elem {
+	return iter(H.slice())
+}
+
+// Union returns a new HashSet with the elements found in
+// either set.
+//
+// e.g. {1,2}.Union({2,3}) => {1,2,3}
+//
+func (H *HashSet) Union(other Set) Set {
+	res := H.with(H.slice())
+	for E := range other.Iter() {
+		res.items[E] = struct{}{}
+	}
+	return res
+}
+
+// Intersect returns a new HashSet with the elements found in
+// both sets.
+//
+// e.g. {1,2}.Intersect({2,3}) => {2}
+//
+func (H *HashSet) Intersect(other Set) Set {
+	res := H.with(nil)
+	for _, E := range H.slice() {
+		if other.Contains(E) {
+			res.items[E] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference returns a new HashSet with the elements that
+// are not found in the other set.
+//
+// e.g. {1,2}.Difference({2,3}) => {1}
+//
+func (H *HashSet) Difference(other Set) Set {
+	res := H.with(nil)
+	for _, E := range H.slice() {
+		if !other.Contains(E) {
+			res.items[E] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Equal returns true if both sets hold the same elements.
+//
+// e.g. {1,2}.Equal({2,1}) => true
+//
+func (H *HashSet) Equal(other Set) bool {
+	slc := H.slice()
+	if len(slc) != other.Len() {
+		return false
+	}
+	for _, E := range slc {
+		if !other.Contains(E) {
+			return false
+		}
+	}
+	return true
+}
+
+// slice returns the elements of the set.
+func (H *HashSet) slice() []Elem {
+	H.rlock()
+	defer H.runlock()
+
+	res := make([]Elem, 0, len(H.items))
+	for E := range H.items {
+		res = append(res, E)
+	}
+	return res
+}
+
+// with returns a new HashSet, thread-safe if this
+// one is, holding the given elements.
+func (H *HashSet) with(slc []Elem) *HashSet {
+	res := &HashSet{items: make(map[Elem]struct{}, len(slc)), concurrent: H.concurrent}
+	for _, E := range slc {
+		res.items[E] = struct{}{}
+	}
+	return res
+}
+
+// lock locks the set for writing, if it is thread-safe.
+func (H *HashSet) lock() {
+	if H.concurrent {
+		H.mu.Lock()
+	}
+}
+
+// unlock undoes lock.
+func (H *HashSet) unlock() {
+	if H.concurrent {
+		H.mu.Unlock()
+	}
+}
+
+// rlock locks the set for reading, if it is thread-safe.
+func (H *HashSet) rlock() {
+	if H.concurrent {
+		H.mu.RLock()
+	}
+}
+
+// runlock undoes rlock.
+func (H *HashSet) runlock() {
+	if H.concurrent {
+		H.mu.RUnlock()
+	}
+}
@@ -0,0 +1,40 @@
+// Package set provides sets of unique elements: HashSet for
+// comparable elements in no particular order, and TreeSet for
+// elements kept in the order of a user defined function.
+package set
+
+// Set is implemented by both HashSet and TreeSet. The operations
+// combining two sets accept any Set, and return a new set of the
+// same kind as the receiving one.
+type Set interface {
+	Add(E Elem) error
+	Remove(E Elem) error
+	Contains(E Elem) bool
+	Len() int
+	Union(other Set) Set
+	Intersect(other Set) Set
+	Difference(other Set) Set
+	Iter() chan Elem
+	Equal(other Set) bool
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// LessFunc is used as a user function to compare elements in a TreeSet.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// iter returns an iterator over the given elements.
+func iter(res []Elem) chan Elem {
+	ch := make(chan Elem, len(res))
+	for _, E := range res {
+		ch <- E
+	}
+	close(ch)
+	return ch
+}
@@ -0,0 +1,161 @@
+package set
+
+import (
+	"sort"
+	"sync"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// kinds returns one set of every kind, each holding the given elements.
+func kinds(slc ...int) []Set {
+	res := []Set{NewHashSet(), NewConcurrentHashSet(), NewTreeSet(intLess), NewConcurrentTreeSet(intLess)}
+	for _, S := range res {
+		for _, x := range slc {
+			S.Add(x)
+		}
+	}
+	return res
+}
+
+// sorted returns the elements of the set in order.
+func sorted(S Set) []int {
+	res := []int{}
+	for x := range S.Iter() {
+		res = append(res, x.(int))
+	}
+	sort.Ints(res)
+	return res
+}
+
+// same returns true if the set holds exactly the given elements.
+func same(S Set, want ...int) bool {
+	got := sorted(S)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAddRemove(t *testing.T) {
+	for _, S := range kinds() {
+		if S.Add(1) != nil || S.Add(2) != nil {
+			t.Errorf("Add should accept a new element.")
+		}
+		if S.Add(1) == nil {
+			t.Errorf("Add should refuse an element that already exists.")
+		}
+		if S.Len() != 2 || !S.Contains(1) || S.Contains(3) {
+			t.Errorf("Add should add elements.")
+		}
+
+		if S.Remove(3) == nil {
+			t.Errorf("Remove should refuse an element that doesn't exist.")
+		}
+		if S.Remove(1) != nil || S.Contains(1) || S.Len() != 1 {
+			t.Errorf("Remove should remove elements.")
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	for _, S := range kinds(1, 2, 3, 4) {
+		for _, O := range kinds(3, 4, 5) {
+			if u := S.Union(O); !same(u, 1, 2, 3, 4, 5) {
+				t.Errorf("Union is broken, got %v.", sorted(u))
+			}
+			if i := S.Intersect(O); !same(i, 3, 4) {
+				t.Errorf("Intersect is broken, got %v.", sorted(i))
+			}
+			if d := S.Difference(O); !same(d, 1, 2) {
+				t.Errorf("Difference is broken, got %v.", sorted(d))
+			}
+			if !same(S, 1, 2, 3, 4) || !same(O, 3, 4, 5) {
+				t.Errorf("The operations should not modify the sets.")
+			}
+		}
+	}
+}
+
+func TestResultKind(t *testing.T) {
+	h, c := NewHashSet(), NewConcurrentTreeSet(intLess)
+
+	if res, ok := h.Union(c).(*HashSet); !ok || res.concurrent {
+		t.Errorf("Union should return a set of the receiving kind.")
+	}
+	if res, ok := c.Intersect(h).(*TreeSet); !ok || !res.concurrent {
+		t.Errorf("Intersect should return a set of the receiving kind.")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for _, S := range kinds(1, 2, 3) {
+		for _, O := range kinds(3, 2, 1) {
+			if !S.Equal(O) {
+				t.Errorf("Equal should hold for the same elements.")
+			}
+		}
+		for _, O := range kinds(1, 2, 4) {
+			if S.Equal(O) {
+				t.Errorf("Equal should not hold for other elements.")
+			}
+		}
+		if S.Equal(kinds(1, 2)[0]) {
+			t.Errorf("Equal should not hold for a smaller set.")
+		}
+	}
+}
+
+func TestTreeSetOrder(t *testing.T) {
+	S := NewTreeSet(intLess)
+	for _, x := range []int{5, 1, 4, 2, 3} {
+		S.Add(x)
+	}
+
+	prev := 0
+	for x := range S.Iter() {
+		if x.(int) != prev+1 {
+			t.Errorf("Iter should return the elements in order.")
+		}
+		prev = x.(int)
+	}
+	if S.First() != 1 || S.Last() != 5 {
+		t.Errorf("First or Last is broken.")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	for _, S := range []Set{NewConcurrentHashSet(), NewConcurrentTreeSet(intLess)} {
+		O := kinds(1, 2, 3)[3]
+
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					S.Add(g*100 + i)
+					S.Contains(i)
+					S.Union(O)
+					O.Intersect(S)
+					if i%2 == 0 {
+						S.Remove(g*100 + i)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		if S.Len() != 200 || len(sorted(S)) != 200 {
+			t.Errorf("Concurrent use should not lose elements.")
+		}
+	}
+}
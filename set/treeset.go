@@ -0,0 +1,269 @@
+package set
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/emnl/goods/redblacktree"
+)
+
+// A treeset keeps its elements in a redblacktree, ordered by a user
+// defined function, and has a lock which is only used if the set was
+// created with NewConcurrentTreeSet.
+//
+// Combining two TreeSets uses the set operations of the redblacktree,
+// so both must use the same LessFunc.
+type TreeSet struct {
+	less       LessFunc
+	tree       *redblacktree.RedBlackTree
+	mu         sync.RWMutex
+	concurrent bool
+}
+
+// NewTreeSet is used as a constructor for the TreeSet
+// struct. The set is not thread-safe.
+//
+// e.g. myset := set.NewTreeSet(intLess)
+//
+func NewTreeSet(lf LessFunc) *TreeSet {
+	return &TreeSet{less: lf, tree: redblacktree.New(redblacktree.LessFunc(lf))}
+}
+
+// NewConcurrentTreeSet is used as a constructor for a thread-safe TreeSet.
+//
+// e.g. myset := set.NewConcurrentTreeSet(intLess)
+//
+func NewConcurrentTreeSet(lf LessFunc) *TreeSet {
+	S := NewTreeSet(lf)
+	S.concurrent = true
+	return S
+}
+
+// Size returns the size of the set.
+//
+// e.g. {1,2,3}.Size() => 3
+//
+func (S *TreeSet) Size() int {
+	S.rlock()
+	defer S.runlock()
+
+	return S.tree.Size()
+}
+
+// Len is an alias for Size().
+func (S *TreeSet) Len() int {
+	return S.Size()
+}
+
+// Empty returns true if the set is empty.
+//
+// e.g. {}.Empty() => true
+//
+func (S *TreeSet) Empty() bool {
+	return S.Size() == 0
+}
+
+// Add puts an element in the set.
+//
+// e.g. {1,2}.Add(3) => {1,2,3}
+//
+func (S *TreeSet) Add(E Elem) error {
+	S.lock()
+	defer S.unlock()
+
+	if S.tree.Contains(E) {
+		return errors.New("Item already exists in Set.")
+	}
+	return S.tree.Add(E)
+}
+
+// Remove takes an element out of the set.
+//
+// e.g. {1,2,3}.Remove(2) => {1,3}
+//
+func (S *TreeSet) Remove(E Elem) error {
+	S.lock()
+	defer S.unlock()
+
+	if S.tree.Remove(E) != nil {
+		return errors.New("Item not found in Set.")
+	}
+	return nil
+}
+
+// Contains returns true if the given element exists
+// within the set.
+//
+// e.g. {1,2,3}.Contains(2) => true
+//
+func (S *TreeSet) Contains(E Elem) bool {
+	S.rlock()
+	defer S.runlock()
+
+	return S.tree.Contains(E)
+}
+
+// First returns the smallest element in the set.
+//
+// e.g. {2,1,3}.First() => 1
+//
+func (S *TreeSet) First() Elem {
+	S.rlock()
+	defer S.runlock()
+
+	return S.tree.First()
+}
+
+// Last returns the biggest element in the set.
+//
+// e.g. {2,1,3}.Last() => 3
+//
+func (S *TreeSet) Last() Elem {
+	S.rlock()
+	defer S.runlock()
+
+	return S.tree.Last()
+}
+
+// Iter returns an iterator over the elements of the set,
+// smallest first.
+//
+// e.g. for x := range {2,1,3}.Iter() { x } => 1, 2, 3
+//
+func (S *TreeSet) Iter() chan Elem {
+	return iter(S.slice())
+}
+
+// Union returns a new TreeSet with the elements found in
+// either set.
+//
+// e.g. {1,2}.Union({2,3}) => {1,2,3}
+//
+func (S *TreeSet) Union(other Set) Set {
+	res := S.with(S.slice())
+	if o, ok := other.(*TreeSet); ok {
+		o.rlock()
+		res.tree.Union(o.tree)
+		o.runlock()
+		return res
+	}
+
+	for E := range other.Iter() {
+		if !res.tree.Contains(E) {
+			res.tree.Add(E)
+		}
+	}
+	return res
+}
+
+// Intersect returns a new TreeSet with the elements found in
+// both sets.
+//
+// e.g. {1,2}.Intersect({2,3}) => {2}
+//
+func (S *TreeSet) Intersect(other Set) Set {
+	slc := S.slice()
+	if o, ok := other.(*TreeSet); ok {
+		res := S.with(slc)
+		o.rlock()
+		res.tree.Intersection(o.tree)
+		o.runlock()
+		return res
+	}
+
+	kept := []Elem{}
+	for _, E := range slc {
+		if other.Contains(E) {
+			kept = append(kept, E)
+		}
+	}
+	return S.with(kept)
+}
+
+// Difference returns a new TreeSet with the elements that
+// are not found in the other set.
+//
+// e.g. {1,2}.Difference({2,3}) => {1}
+//
+func (S *TreeSet) Difference(other Set) Set {
+	slc := S.slice()
+	if o, ok := other.(*TreeSet); ok {
+		res := S.with(slc)
+		o.rlock()
+		res.tree.Difference(o.tree)
+		o.runlock()
+		return res
+	}
+
+	kept := []Elem{}
+	for _, E := range slc {
+		if !other.Contains(E) {
+			kept = append(kept, E)
+		}
+	}
+	return S.with(kept)
+}
+
+// Equal returns true if both sets hold the same elements.
+//
+// e.g. {1,2}.Equal({2,1}) => true
+//
+func (S *TreeSet) Equal(other Set) bool {
+	slc := S.slice()
+	if len(slc) != other.Len() {
+		return false
+	}
+	for _, E := range slc {
+		if !other.Contains(E) {
+			return false
+		}
+	}
+	return true
+}
+
+// slice returns the elements of the set in order.
+func (S *TreeSet) slice() []Elem {
+	S.rlock()
+	defer S.runlock()
+
+	res := make([]Elem, 0, S.tree.Size())
+	for E := range S.tree.InOrder() {
+		res = append(res, E)
+	}
+	return res
+}
+
+// with returns a new TreeSet, thread-safe if this one is,
+// holding the given elements, which must be sorted.
+func (S *TreeSet) with(slc []Elem) *TreeSet {
+	tree, _ := redblacktree.FromSorted(redblacktree.LessFunc(S.less), slc)
+	return &TreeSet{less: S.less, tree: tree, concurrent: S.concurrent}
+}
+
+// lock locks the set for writing, if it is thread-safe.
+func (S *TreeSet) lock() {
+	if S.concurrent {
+		S.mu.Lock()
+	}
+}
+
+// unlock undoes lock.
+func (S *TreeSet) unlock() {
+	if S.concurrent {
+		S.mu.Unlock()
+	}
+}
+
+// rlock locks the set for reading, if it is thread-safe.
+func (S *TreeSet) rlock() {
+	if S.concurrent {
+		S.mu.RLock()
+	}
+}
+
+// runlock undoes rlock.
+func (S *TreeSet) runlock() {
+	if S.concurrent {
+		S.mu.RUnlock()
+	}
+}
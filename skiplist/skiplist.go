@@ -0,0 +1,528 @@
+// Package skiplist provides an ordered set built on a skip list.
+// It can be used as a plain, fast ordered set, or in concurrent mode
+// where readers never take a lock.
+package skiplist
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// maxLevel is the highest level a node can reach. With one in four
+// nodes promoted to the next level, it is plenty for any list that
+// fits in memory.
+const maxLevel = 32
+
+// A skiplist has a user defined function which is used to compare the
+// elements, a head node with a pointer on every level, the number of
+// levels in use, and the size of the list.
+//
+// e.g.
+//     head -> 1 ----------> 4
+//     head -> 1 ---> 3 ---> 4
+//     head -> 1 -> 2 -> 3 -> 4 -> 5
+//
+// In concurrent mode the list follows the lazy skip list algorithm:
+// writers lock the nodes they link or unlink, one at a time, while
+// readers follow the pointers without any lock.
+type SkipList struct {
+	less       LessFunc
+	head       *node
+	level      int
+	size       int64
+	concurrent bool
+}
+
+// The skiplist is made up of nodes with an element and a pointer to the
+// next node on each of its levels. In plain mode every pointer knows how
+// many elements it skips (its span). In concurrent mode the nodes have a
+// lock, and flags telling if they are fully linked or being removed.
+type node struct {
+	elem   Elem
+	next   []atomic.Pointer[node]
+	span   []int
+	mu     sync.Mutex
+	marked atomic.Bool
+	linked atomic.Bool
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// LessFunc is used as a user function to compare elements in the list.
+// It must return true if the first parameter is less then the second.
+// False, if the first and second are equal.
+//
+// e.g. intLess func(a,b interface{}) { return (a.(int) < b.(int)) }
+//
+type LessFunc func(a, b interface{}) bool
+
+// New is used as a constructor for the SkipList
+// struct. The list is not thread-safe.
+//
+// e.g. mylist := skiplist.New(intLess)
+//
+func New(lf LessFunc) *SkipList {
+	return &SkipList{lf, newNode(nil, maxLevel), 1, 0, false}
+}
+
+// NewConcurrent is used as a constructor for a thread-safe SkipList.
+// Contains, First, Last and the iterators never lock, but Rank and Get
+// walk the whole list since no spans are kept.
+//
+// e.g. mylist := skiplist.NewConcurrent(intLess)
+//
+func NewConcurrent(lf LessFunc) *SkipList {
+	return &SkipList{lf, newNode(nil, maxLevel), maxLevel, 0, true}
+}
+
+// Size returns the size of the list.
+//
+// e.g. (1,2,3).Size() => 3
+//
+func (S *SkipList) Size() int {
+	return int(atomic.LoadInt64(&S.size))
+}
+
+// Len is an alias for Size().
+func (S *SkipList) Len() int {
+	return S.Size()
+}
+
+// Empty returns true if the list is empty.
+//
+// e.g. ().Empty() => true
+//
+func (S *SkipList) Empty() bool {
+	return S.Size() == 0
+}
+
+// Add inserts an element into the list at its sorted position.
+//
+// e.g. (1,3).Add(2) => (1,2,3)
+//
+func (S *SkipList) Add(E Elem) error {
+	if S.concurrent {
+		return S.addConcurrent(E)
+	}
+
+	var update [maxLevel]*node
+	var rank [maxLevel + 1]int
+
+	x := S.head
+	for i := S.level - 1; i >= 0; i-- {
+		rank[i] = rank[i+1]
+		for nx := x.next[i].Load(); nx != nil && S.less(nx.elem, E); nx = x.next[i].Load() {
+			rank[i] += x.span[i]
+			x = nx
+		}
+		update[i] = x
+	}
+
+	if nx := x.next[0].Load(); nx != nil && !S.less(E, nx.elem) {
+		return errors.New("Item already exists in List.")
+	}
+
+	lvl := randomLevel()
+	if lvl > S.level {
+		for i := S.level; i < lvl; i++ {
+			rank[i] = 0
+			update[i] = S.head
+			update[i].span[i] = S.Size()
+		}
+		S.level = lvl
+	}
+
+	n := newNode(E, lvl)
+	for i := 0; i < lvl; i++ {
+		n.next[i].Store(update[i].next[i].Load())
+		update[i].next[i].Store(n)
+
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := lvl; i < S.level; i++ {
+		update[i].span[i]++
+	}
+
+	S.size++
+	return nil
+}
+
+// Remove deletes an element from the list.
+//
+// e.g. (1,2,3).Remove(2) => (1,3)
+//
+func (S *SkipList) Remove(E Elem) error {
+	if S.concurrent {
+		return S.removeConcurrent(E)
+	}
+
+	var update [maxLevel]*node
+
+	x := S.head
+	for i := S.level - 1; i >= 0; i-- {
+		for nx := x.next[i].Load(); nx != nil && S.less(nx.elem, E); nx = x.next[i].Load() {
+			x = nx
+		}
+		update[i] = x
+	}
+
+	x = x.next[0].Load()
+	if x == nil || S.less(E, x.elem) {
+		return errors.New("Item not found in List.")
+	}
+
+	for i := 0; i < S.level; i++ {
+		if update[i].next[i].Load() == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i].Store(x.next[i].Load())
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for S.level > 1 && S.head.next[S.level-1].Load() == nil {
+		S.level--
+	}
+
+	S.size--
+	return nil
+}
+
+// Contains returns true if the given element exists
+// within the list.
+//
+// e.g. (1,2,3).Contains(2) => true
+//
+func (S *SkipList) Contains(E Elem) bool {
+	var preds, succs [maxLevel]*node
+	found := S.find(E, &preds, &succs)
+	if found == -1 {
+		return false
+	}
+	n := succs[found]
+	return !S.concurrent || (n.linked.Load() && !n.marked.Load())
+}
+
+// First returns the smallest element in the list.
+//
+// e.g. (1,2,3).First() => 1
+//
+func (S *SkipList) First() Elem {
+	n := S.nextLive(S.head)
+	if n == nil {
+		return nil
+	}
+	return n.elem
+}
+
+// Last returns the biggest element in the list.
+//
+// e.g. (1,2,3).Last() => 3
+//
+func (S *SkipList) Last() Elem {
+	x := S.head
+	for i := S.level - 1; i >= 0; i-- {
+		for nx := x.next[i].Load(); nx != nil; nx = x.next[i].Load() {
+			x = nx
+		}
+	}
+
+	/* A concurrent remove may have marked the node already */
+	if x != S.head && x.marked.Load() {
+		var last *node
+		for n := S.nextLive(S.head); n != nil; n = S.nextLive(n) {
+			last = n
+		}
+		x = last
+	}
+
+	if x == nil || x == S.head {
+		return nil
+	}
+	return x.elem
+}
+
+// Rank returns the position of the element in the list,
+// or -1 if it isn't found. It runs in O(log n) in plain mode
+// and O(n) in concurrent mode.
+//
+// e.g. (1,2,3).Rank(3) => 2
+//
+func (S *SkipList) Rank(E Elem) int {
+	if S.concurrent {
+		i := 0
+		for n := S.nextLive(S.head); n != nil; n = S.nextLive(n) {
+			if !S.less(n.elem, E) {
+				if S.less(E, n.elem) {
+					return -1
+				}
+				return i
+			}
+			i++
+		}
+		return -1
+	}
+
+	rank := 0
+	x := S.head
+	for i := S.level - 1; i >= 0; i-- {
+		for nx := x.next[i].Load(); nx != nil && S.less(nx.elem, E); nx = x.next[i].Load() {
+			rank += x.span[i]
+			x = nx
+		}
+	}
+
+	if nx := x.next[0].Load(); nx != nil && !S.less(E, nx.elem) {
+		return rank
+	}
+	return -1
+}
+
+// Get returns the element at the given position,
+// or nil if it is out of bounds. It runs in O(log n) in
+// plain mode and O(n) in concurrent mode.
+//
+// e.g. (1,2,3).Get(2) => 3
+//
+func (S *SkipList) Get(i int) Elem {
+	if i < 0 {
+		return nil
+	}
+
+	if S.concurrent {
+		for n := S.nextLive(S.head); n != nil; n = S.nextLive(n) {
+			if i == 0 {
+				return n.elem
+			}
+			i--
+		}
+		return nil
+	}
+
+	target := i + 1
+	traversed := 0
+	x := S.head
+	for l := S.level - 1; l >= 0; l-- {
+		for nx := x.next[l].Load(); nx != nil && traversed+x.span[l] <= target; nx = x.next[l].Load() {
+			traversed += x.span[l]
+			x = nx
+		}
+		if traversed == target {
+			return x.elem
+		}
+	}
+	return nil
+}
+
+// Iter returns an iterator over the list, smallest
+// element first.
+//
+// e.g. for x := range (1,2,3).Iter() { x } => 1, 2, 3
+//
+func (S *SkipList) Iter() chan Elem {
+	return S.collect(S.head, nil)
+}
+
+// Range returns an iterator over the elements that are equal
+// or bigger than lo, and less than hi.
+//
+// e.g. for x := range (1,2,3,4).Range(2, 4) { x } => 2, 3
+//
+func (S *SkipList) Range(lo, hi Elem) chan Elem {
+	x := S.head
+	for i := S.level - 1; i >= 0; i-- {
+		for nx := x.next[i].Load(); nx != nil && S.less(nx.elem, lo); nx = x.next[i].Load() {
+			x = nx
+		}
+	}
+	return S.collect(x, hi)
+}
+
+// collect returns a channel with the elements after the given node,
+// up to hi (or the end if hi is nil). The elements are gathered up
+// front, so the channel never blocks.
+func (S *SkipList) collect(from *node, hi Elem) chan Elem {
+	res := []Elem{}
+	for n := S.nextLive(from); n != nil; n = S.nextLive(n) {
+		if hi != nil && !S.less(n.elem, hi) {
+			break
+		}
+		res = append(res, n.elem)
+	}
+
+	ch := make(chan Elem, len(res))
+	for _, E := range res {
+		ch <- E
+	}
+	close(ch)
+	return ch
+}
+
+// nextLive returns the node after n on the bottom level, skipping
+// nodes that are half linked or being removed.
+func (S *SkipList) nextLive(n *node) *node {
+	n = n.next[0].Load()
+	for S.concurrent && n != nil && (!n.linked.Load() || n.marked.Load()) {
+		n = n.next[0].Load()
+	}
+	return n
+}
+
+// find fills in the predecessors and successors of the element on every
+// level, without locking. It returns the highest level where the element
+// was found, or -1.
+func (S *SkipList) find(E Elem, preds, succs *[maxLevel]*node) int {
+	found := -1
+	pred := S.head
+	for l := S.level - 1; l >= 0; l-- {
+		curr := pred.next[l].Load()
+		for curr != nil && S.less(curr.elem, E) {
+			pred = curr
+			curr = pred.next[l].Load()
+		}
+		if found == -1 && curr != nil && !S.less(E, curr.elem) {
+			found = l
+		}
+		preds[l] = pred
+		succs[l] = curr
+	}
+	return found
+}
+
+// addConcurrent is the concurrent version of Add. It locks the
+// predecessors of the new node, checks that nothing changed since
+// they were found, and links the node in from the bottom up.
+func (S *SkipList) addConcurrent(E Elem) error {
+	var preds, succs [maxLevel]*node
+	top := randomLevel()
+
+	for {
+		found := S.find(E, &preds, &succs)
+		if found != -1 {
+			n := succs[found]
+			if !n.marked.Load() {
+				for !n.linked.Load() {
+					runtime.Gosched()
+				}
+				return errors.New("Item already exists in List.")
+			}
+			continue // It is being removed, try again
+		}
+
+		locked, valid := lockPreds(&preds, top, func(l int, pred *node) bool {
+			succ := succs[l]
+			return (succ == nil || !succ.marked.Load()) && pred.next[l].Load() == succ
+		})
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+
+		n := newNode(E, top)
+		for l := 0; l < top; l++ {
+			n.next[l].Store(succs[l])
+		}
+		for l := 0; l < top; l++ {
+			preds[l].next[l].Store(n)
+		}
+		n.linked.Store(true)
+
+		unlockPreds(&preds, locked)
+		atomic.AddInt64(&S.size, 1)
+		return nil
+	}
+}
+
+// removeConcurrent is the concurrent version of Remove. The node is
+// marked first, which removes it logically, and then unlinked from
+// the top down.
+func (S *SkipList) removeConcurrent(E Elem) error {
+	var preds, succs [maxLevel]*node
+	var victim *node
+
+	for {
+		found := S.find(E, &preds, &succs)
+
+		if victim == nil {
+			if found == -1 {
+				return errors.New("Item not found in List.")
+			}
+			n := succs[found]
+			if !n.linked.Load() || n.marked.Load() || len(n.next)-1 != found {
+				return errors.New("Item not found in List.")
+			}
+
+			n.mu.Lock()
+			if n.marked.Load() {
+				n.mu.Unlock()
+				return errors.New("Item not found in List.")
+			}
+			n.marked.Store(true)
+			victim = n
+		}
+
+		top := len(victim.next)
+		locked, valid := lockPreds(&preds, top, func(l int, pred *node) bool {
+			return pred.next[l].Load() == victim
+		})
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+
+		for l := top - 1; l >= 0; l-- {
+			preds[l].next[l].Store(victim.next[l].Load())
+		}
+
+		victim.mu.Unlock()
+		unlockPreds(&preds, locked)
+		atomic.AddInt64(&S.size, -1)
+		return nil
+	}
+}
+
+// lockPreds locks the predecessors on the levels below top, bottom
+// first, and validates each level. It returns the highest level
+// that was locked and whether every level was valid.
+func lockPreds(preds *[maxLevel]*node, top int, valid func(int, *node) bool) (int, bool) {
+	locked := -1
+	for l := 0; l < top; l++ {
+		pred := preds[l]
+		if l == 0 || pred != preds[l-1] {
+			pred.mu.Lock()
+			locked = l
+		}
+		if pred.marked.Load() || !valid(l, pred) {
+			return locked, false
+		}
+	}
+	return locked, true
+}
+
+// unlockPreds unlocks the predecessors locked by lockPreds.
+func unlockPreds(preds *[maxLevel]*node, locked int) {
+	for l := 0; l <= locked; l++ {
+		if l == 0 || preds[l] != preds[l-1] {
+			preds[l].mu.Unlock()
+		}
+	}
+}
+
+// newNode creates a node with the given number of levels.
+func newNode(E Elem, levels int) *node {
+	return &node{elem: E, next: make([]atomic.Pointer[node], levels), span: make([]int, levels)}
+}
+
+// randomLevel picks the number of levels for a new node.
+// Every level has a one in four chance of being promoted.
+func randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && rand.Intn(4) == 0 {
+		lvl++
+	}
+	return lvl
+}
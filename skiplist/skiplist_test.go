@@ -0,0 +1,166 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// toSlice returns the elements of the list in order.
+func toSlice(list *SkipList) []int {
+	res := []int{}
+	for x := range list.Iter() {
+		res = append(res, x.(int))
+	}
+	return res
+}
+
+func TestNew(t *testing.T) {
+	list := New(intLess)
+
+	if list.Size() != 0 || !list.Empty() || list.First() != nil || list.Last() != nil {
+		t.Errorf("New constructor is broken.")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		list.Add(20)
+		list.Add(10)
+		list.Add(30)
+
+		if list.Add(20) == nil {
+			t.Errorf("Add should refuse an element that already exists.")
+		}
+		if list.Size() != 3 {
+			t.Errorf("Add should add elements.")
+		}
+
+		res := toSlice(list)
+		if len(res) != 3 || res[0] != 10 || res[1] != 20 || res[2] != 30 {
+			t.Errorf("Add should keep the elements sorted.")
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		if list.Remove(10) == nil {
+			t.Errorf("Remove should fail on an empty list.")
+		}
+
+		list.Add(10)
+		list.Add(20)
+
+		if list.Remove(10) != nil || list.Size() != 1 || list.Contains(10) {
+			t.Errorf("Remove should remove the element.")
+		}
+		if list.Remove(10) == nil {
+			t.Errorf("Remove should fail on a missing element.")
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		list.Add(10)
+
+		if !list.Contains(10) || list.Contains(20) {
+			t.Errorf("Contains should only find added elements.")
+		}
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		for _, x := range []int{5, 3, 9, 1, 7} {
+			list.Add(x)
+		}
+
+		if list.First() != 1 || list.Last() != 9 {
+			t.Errorf("First and Last should return the smallest and biggest element.")
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		for x := 0; x < 10; x++ {
+			list.Add(x * 2)
+		}
+
+		res := []int{}
+		for x := range list.Range(3, 9) {
+			res = append(res, x.(int))
+		}
+
+		if len(res) != 3 || res[0] != 4 || res[1] != 6 || res[2] != 8 {
+			t.Errorf("Range should return the elements in [lo, hi), got %v.", res)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, list := range []*SkipList{New(intLess), NewConcurrent(intLess)} {
+		for i := 0; i < 500; i++ {
+			list.Add(r.Intn(1000))
+		}
+		for i := 0; i < 200; i++ {
+			list.Remove(r.Intn(1000))
+		}
+
+		for i, x := range toSlice(list) {
+			if list.Rank(x) != i {
+				t.Fatalf("Rank(%d) should be %d, got %d.", x, i, list.Rank(x))
+			}
+			if list.Get(i) != x {
+				t.Fatalf("Get(%d) should be %d.", i, x)
+			}
+		}
+
+		if list.Rank(-1) != -1 || list.Get(-1) != nil || list.Get(list.Size()) != nil {
+			t.Errorf("Rank and Get should handle missing elements.")
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	list := NewConcurrent(intLess)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for x := w; x < 4000; x += 8 {
+				list.Add(x)
+				list.Contains(x - 1)
+			}
+			for x := w; x < 4000; x += 16 {
+				list.Remove(x)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	res := toSlice(list)
+	if len(res) != 2000 || list.Size() != 2000 {
+		t.Fatalf("Concurrent adds and removes should leave 2000 elements, got %d.", len(res))
+	}
+	for i := 1; i < len(res); i++ {
+		if res[i-1] >= res[i] {
+			t.Errorf("The list should stay sorted.")
+		}
+	}
+	for _, x := range res {
+		if x%16 < 8 {
+			t.Errorf("%d should have been removed.", x)
+		}
+	}
+}
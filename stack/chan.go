@@ -0,0 +1,55 @@
+package stack
+
+import (
+	"context"
+	"reflect"
+)
+
+// FromChan builds a stack from the elements received on a channel,
+// so the last one to arrive is on top. It returns once the channel
+// is closed. The channel may be of any element type; FromChan panics
+// if it isn't a channel that can be received from.
+//
+// e.g. mystack := stack.FromChan(tasks)
+//
+func FromChan(ch interface{}) *Stack {
+	S := New()
+	v := reflect.ValueOf(ch)
+	if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.RecvDir == 0 {
+		panic("FromChan needs a channel to receive from.")
+	}
+	for {
+		x, ok := v.Recv()
+		if !ok {
+			return S
+		}
+		S.Push(x.Interface())
+	}
+}
+
+// ToChan returns a channel which receives the elements of the stack,
+// popping them one at a time as they are sent. The channel is closed
+// once the stack is empty, or when the context is done; an element
+// which could not be sent by then is pushed back onto the stack.
+//
+// e.g. for x := range mystack.ToChan(ctx) { x }
+//
+func (S *Stack) ToChan(ctx context.Context) <-chan Elem {
+	ch := make(chan Elem)
+	go func() {
+		defer close(ch)
+		for {
+			V, ok := S.TakeFirst()
+			if !ok {
+				return
+			}
+			select {
+			case ch <- V:
+			case <-ctx.Done():
+				S.Push(V)
+				return
+			}
+		}
+	}()
+	return ch
+}
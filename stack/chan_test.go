@@ -0,0 +1,96 @@
+package stack
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan string, 3)
+	ch <- "a"
+	ch <- "b"
+	ch <- "c"
+	close(ch)
+
+	stack := FromChan(ch)
+	if stack.Size() != 3 || stack.Pop() != "c" || stack.Pop() != "b" {
+		t.Errorf("FromChan should push the elements in the order they arrive.")
+	}
+}
+
+func TestFromChanNotChan(t *testing.T) {
+	for _, x := range []interface{}{nil, 1, make(chan<- string)} {
+		func() {
+			defer func() {
+				if r := recover(); r != "FromChan needs a channel to receive from." {
+					t.Errorf("FromChan should panic clearly on %T, got %v.", x, r)
+				}
+			}()
+			FromChan(x)
+		}()
+	}
+}
+
+func TestToChan(t *testing.T) {
+	stack := New()
+	for i := 0; i < 5; i++ {
+		stack.Push(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := stack.ToChan(ctx)
+	if <-ch != 4 || <-ch != 3 {
+		t.Errorf("ToChan should send the elements from the top.")
+	}
+	cancel()
+
+	n := 2
+	for range ch {
+		n++
+	}
+	if n+stack.Size() != 5 || (n < 5 && stack.Peek() != 4-n) {
+		t.Errorf("ToChan should leave the elements it didn't send.")
+	}
+
+	for range stack.ToChan(context.Background()) {
+	}
+	if !stack.Empty() {
+		t.Errorf("ToChan should drain the stack.")
+	}
+}
+
+func TestToChanConcurrent(t *testing.T) {
+	stack := New()
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+
+	/* Several drains at once must each get distinct elements */
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	res := []Elem{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := range stack.ToChan(context.Background()) {
+				mu.Lock()
+				res = append(res, x)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[Elem]bool{}
+	for _, x := range res {
+		if x == nil || seen[x] {
+			t.Fatalf("ToChan should send every element once, got %v again.", x)
+		}
+		seen[x] = true
+	}
+	if len(seen) != 1000 || !stack.Empty() {
+		t.Errorf("ToChan should drain the stack, got %d.", len(seen))
+	}
+}
@@ -7,6 +7,10 @@ import "github.com/emnl/goods/linkedlist"
 
 // Stack uses a linkedlist to behave as a last-in-first-out
 // stack.
+//
+// The JSON, text, binary and gob encodings come from the linkedlist.
+// They list the elements from the top of the stack to the bottom, so
+// a decoded stack pops in the same order.
 type Stack struct {
 	linkedlist.LinkedList
 }
@@ -37,12 +41,7 @@ func (S *Stack) Push(V Elem) {
 //       --^-- .Pop() => 2
 //
 func (S *Stack) Pop() Elem {
-	if S.Empty() {
-		return nil
-	}
-
-	result := S.First()
-	S.RemoveFirst()
+	result, _ := S.TakeFirst()
 	return result
 }
 
@@ -1,6 +1,9 @@
 package stack
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"testing"
 )
 
@@ -35,3 +38,55 @@ func TestPeek(t *testing.T) {
 		t.Errorf("Peek should return the first item on the stack, but not remove it.")
 	}
 }
+
+func TestMarshalJSON(t *testing.T) {
+	stack := New()
+	stack.Push(1)
+	stack.Push(2)
+
+	data, err := json.Marshal(stack)
+	if err != nil || string(data) != "[2,1]" {
+		t.Errorf("MarshalJSON should write the elements from top to bottom, got %s.", data)
+	}
+
+	stack = New()
+	json.Unmarshal([]byte(`["a","b"]`), stack)
+
+	if stack.Pop() != "a" || stack.Pop() != "b" {
+		t.Errorf("UnmarshalJSON should restore the stack from top to bottom.")
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	stack := New()
+	stack.Push(1)
+	stack.Push(2)
+
+	data, err := stack.MarshalText()
+	if err != nil || string(data) != "[2,1]" {
+		t.Errorf("MarshalText should write the elements in the same order as MarshalJSON, got %s.", data)
+	}
+
+	res := New()
+	if res.UnmarshalText(data) != nil || res.Pop() != 2.0 {
+		t.Errorf("UnmarshalText should restore the order.")
+	}
+}
+
+func TestGob(t *testing.T) {
+	stack := New()
+	stack.Push(1)
+	stack.Push(2)
+
+	buf := new(bytes.Buffer)
+	gob.NewEncoder(buf).Encode(stack)
+
+	stack = New()
+	if err := gob.NewDecoder(buf).Decode(stack); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+
+	if stack.Pop() != 2 || stack.Pop() != 1 {
+		t.Errorf("GobDecode should restore the stack from top to bottom.")
+	}
+}
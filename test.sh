@@ -20,3 +20,59 @@ cd ..
 cd redblacktree
 go test
 cd ..
+
+cd intervaltree
+go test
+cd ..
+
+cd skiplist
+go test
+cd ..
+
+cd btree
+go test
+cd ..
+
+cd trie
+go test
+cd ..
+
+cd heap
+go test
+cd ..
+
+cd set
+go test
+cd ..
+
+cd graph
+go test
+cd ..
+
+cd unionfind
+go test
+cd ..
+
+cd bloom
+go test
+cd ..
+
+cd ringbuffer
+go test
+cd ..
+
+cd journal
+go test
+cd ..
+
+cd durablequeue
+go test
+cd ..
+
+cd deque
+go test
+cd ..
+
+cd delayqueue
+go test
+cd ..
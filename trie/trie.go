@@ -0,0 +1,370 @@
+// Package trie provides a compressed trie (radix tree) mapping
+// string keys to values. Unlike the trees, it can find every key
+// that starts with a prefix, or the longest key that a string
+// starts with.
+package trie
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A trie has a root node, a size, and a lock which is only
+// used if the trie was created with NewConcurrent.
+//
+// Every edge is labeled with a string, and a node with a single child
+// is merged with it unless it holds a key. The keys are the labels
+// along the path from the root to a node holding a value.
+//
+// e.g. romane, romanus, rubens:
+//                 r
+//               /   \
+//           oman     ubens*
+//           /  \
+//          e*  us*
+//
+type Trie struct {
+	root       *node
+	size       int
+	mu         sync.RWMutex
+	concurrent bool
+}
+
+// The trie is made up of nodes with the label of the edge leading to
+// them, a value if the path to the node is a key, and the children
+// sorted by the first byte of their label.
+type node struct {
+	prefix   string
+	value    Elem
+	leaf     bool
+	children []*node
+}
+
+// Elem is used as a generic for any type of value.
+type Elem interface{}
+
+// Entry is a key and its value, as returned by the iterators.
+type Entry struct {
+	Key   string
+	Value Elem
+}
+
+// New is used as a constructor for the Trie struct.
+// The trie is not thread-safe.
+//
+// e.g. mytrie := trie.New()
+//
+func New() *Trie {
+	return &Trie{root: &node{}}
+}
+
+// NewConcurrent is used as a constructor for a thread-safe Trie.
+//
+// e.g. mytrie := trie.NewConcurrent()
+//
+func NewConcurrent() *Trie {
+	return &Trie{root: &node{}, concurrent: true}
+}
+
+// Size returns the number of keys in the trie.
+//
+// e.g. (a:1, b:2).Size() => 2
+//
+func (T *Trie) Size() int {
+	T.rlock()
+	defer T.runlock()
+
+	return T.size
+}
+
+// Len is an alias for Size().
+func (T *Trie) Len() int {
+	return T.Size()
+}
+
+// Empty returns true if the trie is empty.
+//
+// e.g. ().Empty() => true
+//
+func (T *Trie) Empty() bool {
+	return T.Size() == 0
+}
+
+// Insert maps the key to the given value. If the key already
+// exists, an error is returned and the Trie is left untouched.
+//
+// e.g. (a:1).Insert("ab", 2) => (a:1, ab:2)
+//
+func (T *Trie) Insert(key string, V Elem) error {
+	T.lock()
+	defer T.unlock()
+
+	n, search := T.root, key
+	for len(search) > 0 {
+		i, child := n.child(search[0])
+		if child == nil {
+			n.addChild(&node{search, V, true, nil})
+			T.size++
+			return nil
+		}
+
+		common := commonPrefix(search, child.prefix)
+		if common < len(child.prefix) {
+			/* The key ends or branches off inside the label, split it */
+			mid := &node{prefix: child.prefix[:common], children: []*node{child}}
+			child.prefix = child.prefix[common:]
+			n.children[i] = mid
+			child = mid
+		}
+
+		n, search = child, search[common:]
+	}
+
+	if n.leaf {
+		return errors.New("Key already exists in Trie.")
+	}
+	n.value, n.leaf = V, true
+	T.size++
+	return nil
+}
+
+// Get returns the value of the key, and true if the key exists.
+//
+// e.g. (a:1, ab:2).Get("ab") => 2, true
+//
+func (T *Trie) Get(key string) (Elem, bool) {
+	T.rlock()
+	defer T.runlock()
+
+	n, _ := T.find(key)
+	if n == nil || !n.leaf {
+		return nil, false
+	}
+	return n.value, true
+}
+
+// Contains returns true if the key exists within the trie.
+//
+// e.g. (a:1, ab:2).Contains("a") => true
+//
+func (T *Trie) Contains(key string) bool {
+	_, ok := T.Get(key)
+	return ok
+}
+
+// Delete removes the key and its value from the trie.
+//
+// e.g. (a:1, ab:2).Delete("a") => (ab:2)
+//
+func (T *Trie) Delete(key string) error {
+	T.lock()
+	defer T.unlock()
+
+	n, parent := T.find(key)
+	if n == nil || !n.leaf {
+		return errors.New("Key not found in Trie.")
+	}
+
+	n.value, n.leaf = nil, false
+	T.size--
+
+	if n != T.root && len(n.children) == 0 {
+		parent.removeChild(n.prefix[0])
+		n = parent
+	}
+	if n != T.root && !n.leaf && len(n.children) == 1 {
+		n.mergeChild()
+	}
+	return nil
+}
+
+// LongestPrefix returns the longest key that the string starts
+// with, its value, and true if there is such a key.
+//
+// e.g. (/:1, /api:2).LongestPrefix("/api/users") => "/api", 2, true
+//
+func (T *Trie) LongestPrefix(s string) (string, Elem, bool) {
+	T.rlock()
+	defer T.runlock()
+
+	var last *node
+	length := 0
+
+	n, search := T.root, s
+	for {
+		if n.leaf {
+			last, length = n, len(s)-len(search)
+		}
+		if len(search) == 0 {
+			break
+		}
+
+		_, child := n.child(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			break
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+
+	if last == nil {
+		return "", nil, false
+	}
+	return s[:length], last.value, true
+}
+
+// WalkPrefix returns an iterator over the keys that start
+// with the given prefix, in order.
+//
+// e.g. for e := range (a:1, ab:2, b:3).WalkPrefix("a") { e.Key } => a, ab
+//
+func (T *Trie) WalkPrefix(prefix string) chan Entry {
+	T.rlock()
+	defer T.runlock()
+
+	res := []Entry{}
+	n, search := T.root, prefix
+	for len(search) > 0 {
+		_, child := n.child(search[0])
+		if child == nil {
+			return entries(res)
+		}
+		if strings.HasPrefix(child.prefix, search) {
+			/* The prefix ends inside the label */
+			prefix += child.prefix[len(search):]
+			n = child
+			break
+		}
+		if !strings.HasPrefix(search, child.prefix) {
+			return entries(res)
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+
+	walk(n, prefix, &res)
+	return entries(res)
+}
+
+// Iter returns an iterator over every key in the trie, in order.
+//
+// e.g. for e := range (b:2, a:1).Iter() { e.Key } => a, b
+//
+func (T *Trie) Iter() chan Entry {
+	T.rlock()
+	defer T.runlock()
+
+	res := make([]Entry, 0, T.size)
+	walk(T.root, "", &res)
+	return entries(res)
+}
+
+// find returns the node at the end of the key, and its parent.
+// The node is nil if the key leads nowhere.
+func (T *Trie) find(key string) (*node, *node) {
+	var parent *node
+	n, search := T.root, key
+	for len(search) > 0 {
+		_, child := n.child(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return nil, nil
+		}
+		parent, n, search = n, child, search[len(child.prefix):]
+	}
+	return n, parent
+}
+
+// lock locks the trie for writing, if it is thread-safe.
+func (T *Trie) lock() {
+	if T.concurrent {
+		T.mu.Lock()
+	}
+}
+
+// unlock undoes lock.
+func (T *Trie) unlock() {
+	if T.concurrent {
+		T.mu.Unlock()
+	}
+}
+
+// rlock locks the trie for reading, if it is thread-safe.
+func (T *Trie) rlock() {
+	if T.concurrent {
+		T.mu.RLock()
+	}
+}
+
+// runlock undoes rlock.
+func (T *Trie) runlock() {
+	if T.concurrent {
+		T.mu.RUnlock()
+	}
+}
+
+// walk appends the keys in the subtree to res, in order. The key
+// is the key of the given node.
+func walk(n *node, key string, res *[]Entry) {
+	if n.leaf {
+		*res = append(*res, Entry{key, n.value})
+	}
+	for _, c := range n.children {
+		walk(c, key+c.prefix, res)
+	}
+}
+
+// entries returns an iterator over the given entries.
+func entries(res []Entry) chan Entry {
+	ch := make(chan Entry, len(res))
+	for _, e := range res {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// child returns the position of the child whose label starts with
+// the given byte, and the child. The child is nil if there is none.
+func (N *node) child(b byte) (int, *node) {
+	i := sort.Search(len(N.children), func(i int) bool {
+		return N.children[i].prefix[0] >= b
+	})
+	if i < len(N.children) && N.children[i].prefix[0] == b {
+		return i, N.children[i]
+	}
+	return i, nil
+}
+
+// addChild inserts a child whose label doesn't
+// start like any other child's label.
+func (N *node) addChild(c *node) {
+	i, _ := N.child(c.prefix[0])
+	N.children = append(N.children, nil)
+	copy(N.children[i+1:], N.children[i:])
+	N.children[i] = c
+}
+
+// removeChild removes the child whose label starts with the given byte.
+func (N *node) removeChild(b byte) {
+	i, _ := N.child(b)
+	copy(N.children[i:], N.children[i+1:])
+	N.children[len(N.children)-1] = nil
+	N.children = N.children[:len(N.children)-1]
+}
+
+// mergeChild merges the node with its only child.
+func (N *node) mergeChild() {
+	c := N.children[0]
+	N.prefix += c.prefix
+	N.value, N.leaf = c.value, c.leaf
+	N.children = c.children
+}
@@ -0,0 +1,221 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// keys returns the keys of the entries.
+func keys(ch chan Entry) []string {
+	res := []string{}
+	for e := range ch {
+		res = append(res, e.Key)
+	}
+	return res
+}
+
+// checkNode reports every node that should have been merged
+// or removed, and every child list out of order.
+func checkNode(t *testing.T, trie *Trie, n *node) {
+	if n != trie.root && !n.leaf && len(n.children) < 2 {
+		t.Errorf("Node %q should have been merged.", n.prefix)
+	}
+	for i, c := range n.children {
+		if c.prefix == "" {
+			t.Errorf("Child of %q has an empty label.", n.prefix)
+			continue
+		}
+		if i > 0 && n.children[i-1].prefix[0] >= c.prefix[0] {
+			t.Errorf("Children of %q are out of order.", n.prefix)
+		}
+		checkNode(t, trie, c)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, trie := range []*Trie{New(), NewConcurrent()} {
+		if trie.Size() != 0 || !trie.Empty() || len(trie.Iter()) != 0 {
+			t.Errorf("New constructor is broken.")
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	trie := New()
+	for i, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rom", ""} {
+		if trie.Insert(k, i) != nil {
+			t.Errorf("Insert should accept a new key.")
+		}
+	}
+	checkNode(t, trie, trie.root)
+
+	if trie.Insert("rom", 10) == nil {
+		t.Errorf("Insert should refuse a key that already exists.")
+	}
+	if v, ok := trie.Get("rom"); !ok || v != 5 {
+		t.Errorf("Insert should not replace the value of an existing key.")
+	}
+	if trie.Size() != 7 {
+		t.Errorf("Insert should add keys.")
+	}
+
+	if v, ok := trie.Get(""); !ok || v != 6 {
+		t.Errorf("Get should find the empty key.")
+	}
+	if _, ok := trie.Get("roma"); ok {
+		t.Errorf("Get should not find a key that is only a prefix.")
+	}
+	if !trie.Contains("ruber") || trie.Contains("rubers") {
+		t.Errorf("Contains is broken.")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	trie := New()
+	for _, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber"} {
+		trie.Insert(k, nil)
+	}
+
+	if trie.Delete("roman") == nil || trie.Delete("rubicon") == nil {
+		t.Errorf("Delete should refuse a key that doesn't exist.")
+	}
+	for _, k := range []string{"romanus", "ruber", "romulus"} {
+		if trie.Delete(k) != nil {
+			t.Errorf("Delete should delete an existing key.")
+		}
+		checkNode(t, trie, trie.root)
+	}
+
+	if got := keys(trie.Iter()); strings.Join(got, ",") != "romane,rubens" {
+		t.Errorf("Delete should leave the other keys, got %v.", got)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	trie, want := New(), map[string]int{}
+
+	for i := 0; i < 5000; i++ {
+		b := make([]byte, r.Intn(6))
+		for j := range b {
+			b[j] = "abc"[r.Intn(3)]
+		}
+		k := string(b)
+
+		if r.Intn(3) == 0 {
+			_, ok := want[k]
+			if (trie.Delete(k) == nil) != ok {
+				t.Fatalf("Delete(%q) disagrees with a map.", k)
+			}
+			delete(want, k)
+		} else {
+			_, ok := want[k]
+			if (trie.Insert(k, i) == nil) == ok {
+				t.Fatalf("Insert(%q) disagrees with a map.", k)
+			}
+			if !ok {
+				want[k] = i
+			}
+		}
+	}
+	checkNode(t, trie, trie.root)
+
+	sorted := []string{}
+	for k := range want {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	got := keys(trie.Iter())
+	if strings.Join(got, ",") != strings.Join(sorted, ",") || trie.Size() != len(want) {
+		t.Errorf("Iter should return every key in order.")
+	}
+	for k, v := range want {
+		if x, ok := trie.Get(k); !ok || x != v {
+			t.Errorf("Get(%q) disagrees with a map.", k)
+		}
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	trie := New()
+	trie.Insert("/", 1)
+	trie.Insert("/api", 2)
+	trie.Insert("/api/users", 3)
+	trie.Insert("/apix", 4)
+
+	tests := []struct {
+		s, key string
+		value  Elem
+	}{
+		{"/api/users/7", "/api/users", 3},
+		{"/api/user", "/api", 2},
+		{"/apixyz", "/apix", 4},
+		{"/ap", "/", 1},
+		{"/", "/", 1},
+	}
+	for _, test := range tests {
+		key, v, ok := trie.LongestPrefix(test.s)
+		if !ok || key != test.key || v != test.value {
+			t.Errorf("LongestPrefix(%q) => %q, %v.", test.s, key, v)
+		}
+	}
+
+	if _, _, ok := trie.LongestPrefix("api"); ok {
+		t.Errorf("LongestPrefix should fail when no key is a prefix.")
+	}
+}
+
+func TestWalkPrefix(t *testing.T) {
+	trie := New()
+	for _, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rom"} {
+		trie.Insert(k, nil)
+	}
+
+	tests := map[string]string{
+		"rom":    "rom,romane,romanus,romulus",
+		"roma":   "romane,romanus",
+		"ru":     "rubens,ruber",
+		"rubens": "rubens",
+		"rubic":  "",
+		"x":      "",
+		"":       "rom,romane,romanus,romulus,rubens,ruber",
+	}
+	for prefix, want := range tests {
+		if got := strings.Join(keys(trie.WalkPrefix(prefix)), ","); got != want {
+			t.Errorf("WalkPrefix(%q) => %v, want %v.", prefix, got, want)
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	trie := NewConcurrent()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				k := string(rune('a'+g)) + strings.Repeat("x", i%10) + string(rune('0'+i%7))
+				trie.Insert(k, i)
+				trie.Get(k)
+				trie.LongestPrefix(k + "y")
+				for range trie.WalkPrefix(k[:1]) {
+				}
+				if i%3 == 0 {
+					trie.Delete(k)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	checkNode(t, trie, trie.root)
+	if len(keys(trie.Iter())) != trie.Size() {
+		t.Errorf("Size should match the number of keys.")
+	}
+}
@@ -0,0 +1,240 @@
+// Package unionfind provides a disjoint-set forest, which keeps
+// elements in groups that can be merged, and tells in nearly O(1)
+// which group an element belongs to.
+package unionfind
+
+import (
+	"errors"
+	"sync"
+)
+
+// A unionfind has the parent and rank of every element, the size of
+// every set, the elements in the order they were added, the number of
+// sets, and a lock which is only used if the unionfind was created
+// with NewConcurrent.
+//
+// Every set is a tree with its root as the representative. Find points
+// every element on its way straight at the root (path compression), and
+// Union puts the lower tree under the higher one (union by rank).
+//
+// e.g. {1,2,3} {4}:
+//        1     4
+//       / \
+//      2   3
+//
+type UnionFind struct {
+	parent     map[Elem]Elem
+	rank       map[Elem]int
+	size       map[Elem]int
+	order      []Elem
+	sets       int
+	mu         sync.Mutex
+	concurrent bool
+}
+
+// Elem is used as a generic for any type of comparable value.
+type Elem interface{}
+
+// New is used as a constructor for the UnionFind
+// struct. It is not thread-safe.
+//
+// e.g. myuf := unionfind.New()
+//
+func New() *UnionFind {
+	return &UnionFind{parent: map[Elem]Elem{}, rank: map[Elem]int{}, size: map[Elem]int{}}
+}
+
+// NewConcurrent is used as a constructor for a thread-safe UnionFind.
+// Since Find modifies the forest, every method takes the same lock.
+//
+// e.g. myuf := unionfind.NewConcurrent()
+//
+func NewConcurrent() *UnionFind {
+	U := New()
+	U.concurrent = true
+	return U
+}
+
+// Size returns the number of elements.
+//
+// e.g. ({1,2} {3}).Size() => 3
+//
+func (U *UnionFind) Size() int {
+	U.lock()
+	defer U.unlock()
+
+	return len(U.order)
+}
+
+// Len is an alias for Size().
+func (U *UnionFind) Len() int {
+	return U.Size()
+}
+
+// Empty returns true if there are no elements.
+//
+// e.g. ().Empty() => true
+//
+func (U *UnionFind) Empty() bool {
+	return U.Size() == 0
+}
+
+// Count returns the number of sets.
+//
+// e.g. ({1,2} {3}).Count() => 2
+//
+func (U *UnionFind) Count() int {
+	U.lock()
+	defer U.unlock()
+
+	return U.sets
+}
+
+// MakeSet adds the element in a set of its own.
+//
+// e.g. ({1,2}).MakeSet(3) => ({1,2} {3})
+//
+func (U *UnionFind) MakeSet(E Elem) error {
+	U.lock()
+	defer U.unlock()
+
+	if _, ok := U.parent[E]; ok {
+		return errors.New("Item already exists in UnionFind.")
+	}
+	U.parent[E] = E
+	U.rank[E] = 0
+	U.size[E] = 1
+	U.order = append(U.order, E)
+	U.sets++
+	return nil
+}
+
+// Find returns the representative of the set holding the element,
+// and true if the element exists. Two elements are in the same set
+// if they have the same representative.
+//
+// e.g. ({1,2} {3}).Find(2) => 1, true
+//
+func (U *UnionFind) Find(E Elem) (Elem, bool) {
+	U.lock()
+	defer U.unlock()
+
+	if _, ok := U.parent[E]; !ok {
+		return nil, false
+	}
+	return U.find(E), true
+}
+
+// Union merges the sets holding the two elements.
+//
+// e.g. ({1,2} {3}).Union(2, 3) => ({1,2,3})
+//
+func (U *UnionFind) Union(a, b Elem) error {
+	U.lock()
+	defer U.unlock()
+
+	_, ok := U.parent[a]
+	_, ok2 := U.parent[b]
+	if !ok || !ok2 {
+		return errors.New("Item not found in UnionFind.")
+	}
+
+	x, y := U.find(a), U.find(b)
+	if x == y {
+		return nil
+	}
+	if U.rank[x] < U.rank[y] {
+		x, y = y, x
+	}
+
+	U.parent[y] = x
+	U.size[x] += U.size[y]
+	delete(U.size, y)
+	if U.rank[x] == U.rank[y] {
+		U.rank[x]++
+	}
+	U.sets--
+	return nil
+}
+
+// Connected returns true if both elements exist
+// and are in the same set.
+//
+// e.g. ({1,2} {3}).Connected(1, 2) => true
+//
+func (U *UnionFind) Connected(a, b Elem) bool {
+	U.lock()
+	defer U.unlock()
+
+	_, ok := U.parent[a]
+	_, ok2 := U.parent[b]
+	return ok && ok2 && U.find(a) == U.find(b)
+}
+
+// SetSize returns the size of the set holding the element,
+// or 0 if the element doesn't exist.
+//
+// e.g. ({1,2} {3}).SetSize(1) => 2
+//
+func (U *UnionFind) SetSize(E Elem) int {
+	U.lock()
+	defer U.unlock()
+
+	if _, ok := U.parent[E]; !ok {
+		return 0
+	}
+	return U.size[U.find(E)]
+}
+
+// Sets returns every set. The sets are ordered by their first
+// element, and the elements by the order they were added.
+//
+// e.g. ({1,3} {2}).Sets() => [[1 3] [2]]
+//
+func (U *UnionFind) Sets() [][]Elem {
+	U.lock()
+	defer U.unlock()
+
+	res := make([][]Elem, 0, U.sets)
+	index := make(map[Elem]int, U.sets)
+	for _, E := range U.order {
+		root := U.find(E)
+		i, ok := index[root]
+		if !ok {
+			i = len(res)
+			index[root] = i
+			res = append(res, make([]Elem, 0, U.size[root]))
+		}
+		res[i] = append(res[i], E)
+	}
+	return res
+}
+
+// find returns the root of the element's tree, and points
+// every element on the way straight at it.
+func (U *UnionFind) find(E Elem) Elem {
+	root := E
+	for U.parent[root] != root {
+		root = U.parent[root]
+	}
+	for E != root {
+		next := U.parent[E]
+		U.parent[E] = root
+		E = next
+	}
+	return root
+}
+
+// lock locks the unionfind, if it is thread-safe.
+func (U *UnionFind) lock() {
+	if U.concurrent {
+		U.mu.Lock()
+	}
+}
+
+// unlock undoes lock.
+func (U *UnionFind) unlock() {
+	if U.concurrent {
+		U.mu.Unlock()
+	}
+}
@@ -0,0 +1,126 @@
+package unionfind
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, uf := range []*UnionFind{New(), NewConcurrent()} {
+		if uf.Size() != 0 || !uf.Empty() || uf.Count() != 0 || len(uf.Sets()) != 0 {
+			t.Errorf("New constructor is broken.")
+		}
+	}
+}
+
+func TestMakeSet(t *testing.T) {
+	uf := New()
+	if uf.MakeSet(1) != nil || uf.MakeSet("a") != nil {
+		t.Errorf("MakeSet should accept a new element.")
+	}
+	if uf.MakeSet(1) == nil {
+		t.Errorf("MakeSet should refuse an element that already exists.")
+	}
+	if uf.Size() != 2 || uf.Count() != 2 || uf.SetSize("a") != 1 {
+		t.Errorf("MakeSet should add sets of one.")
+	}
+	if r, ok := uf.Find("a"); !ok || r != "a" {
+		t.Errorf("A new element should be its own representative.")
+	}
+	if _, ok := uf.Find(2); ok || uf.SetSize(2) != 0 {
+		t.Errorf("A missing element should not be found.")
+	}
+}
+
+func TestUnion(t *testing.T) {
+	uf := New()
+	for i := 1; i <= 6; i++ {
+		uf.MakeSet(i)
+	}
+
+	uf.Union(1, 3)
+	uf.Union(4, 5)
+	uf.Union(5, 3)
+	if uf.Union(1, 7) == nil {
+		t.Errorf("Union should refuse a missing element.")
+	}
+	if uf.Union(1, 4) != nil {
+		t.Errorf("Union of a set with itself should do nothing.")
+	}
+
+	if !uf.Connected(1, 5) || uf.Connected(1, 2) || uf.Connected(1, 7) {
+		t.Errorf("Connected is broken.")
+	}
+	if uf.Count() != 3 || uf.SetSize(4) != 4 || uf.SetSize(6) != 1 {
+		t.Errorf("Union should merge the sets.")
+	}
+	if got := fmt.Sprint(uf.Sets()); got != "[[1 3 4 5] [2] [6]]" {
+		t.Errorf("Sets is broken, got %v.", got)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	uf, label := New(), make([]int, 200)
+	for i := range label {
+		uf.MakeSet(i)
+		label[i] = i
+	}
+
+	for k := 0; k < 150; k++ {
+		a, b := r.Intn(200), r.Intn(200)
+		uf.Union(a, b)
+
+		/* Relabel the slow way */
+		old := label[b]
+		for i := range label {
+			if label[i] == old {
+				label[i] = label[a]
+			}
+		}
+	}
+
+	for k := 0; k < 1000; k++ {
+		a, b := r.Intn(200), r.Intn(200)
+		if uf.Connected(a, b) != (label[a] == label[b]) {
+			t.Fatalf("Connected(%d, %d) is wrong.", a, b)
+		}
+	}
+	total := 0
+	for _, set := range uf.Sets() {
+		total += len(set)
+		if uf.SetSize(set[0]) != len(set) {
+			t.Errorf("SetSize should match the set.")
+		}
+	}
+	if total != 200 {
+		t.Errorf("Sets should hold every element.")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	uf := NewConcurrent()
+	for i := 0; i < 100; i++ {
+		uf.MakeSet(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i+4 < 100; i += 4 {
+				uf.Union(i, i+4)
+				uf.Connected(i, g)
+				uf.Sets()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if uf.Count() != 4 {
+		t.Errorf("Concurrent unions should leave 4 sets, got %d.", uf.Count())
+	}
+}